@@ -0,0 +1,515 @@
+// Package agent implements the ZehraGuard endpoint agent: it collects
+// behavioral telemetry (keystroke/mouse dynamics, file access, network
+// activity, system load) for a user session and streams it to the
+// ZehraGuard backend for insider-threat analysis.
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/shirou/gopsutil/v3/cpu"
+	"github.com/shirou/gopsutil/v3/mem"
+	"github.com/shirou/gopsutil/v3/process"
+
+	"github.com/yashab-cyber/zehraguard/agents/buffer"
+	"github.com/yashab-cyber/zehraguard/agents/collectors"
+	"github.com/yashab-cyber/zehraguard/agents/transport"
+)
+
+// Event represents a behavioral event
+type Event struct {
+	UserID      string                 `json:"user_id"`
+	EventType   string                 `json:"event_type"`
+	Timestamp   time.Time              `json:"timestamp"`
+	SourceIP    string                 `json:"source_ip"`
+	UserAgent   string                 `json:"user_agent"`
+	EventData   map[string]interface{} `json:"event_data"`
+	ProcessedAt time.Time              `json:"processed_at"`
+}
+
+// FileAccessEvent represents file system access
+type FileAccessEvent struct {
+	FilePath    string    `json:"file_path"`
+	AccessType  string    `json:"access_type"`
+	FileSize    int64     `json:"file_size"`
+	FileType    string    `json:"file_type"`
+	ProcessName string    `json:"process_name"`
+	AccessTime  time.Time `json:"access_time"`
+	Permissions string    `json:"permissions"`
+}
+
+// NetworkEvent represents network activity
+type NetworkEvent struct {
+	DestinationIP   string `json:"destination_ip"`
+	DestinationPort int    `json:"destination_port"`
+	Protocol        string `json:"protocol"`
+	DataVolume      int64  `json:"data_volume"`
+	Domain          string `json:"domain"`
+	RequestType     string `json:"request_type"`
+	UserAgent       string `json:"user_agent"`
+}
+
+// BehavioralAgent collects behavioral data
+type BehavioralAgent struct {
+	userID      string
+	redisClient redis.UniversalClient
+	mux         *transport.Multiplexer
+	buffer      *buffer.Buffer
+	supervisor  *Supervisor
+	config      *AgentConfig
+	collectors  []collectors.Collector
+	registry    *prometheus.Registry
+	ctx         context.Context
+	cancel      context.CancelFunc
+}
+
+// AgentConfig holds agent configuration
+type AgentConfig struct {
+	Redis     RedisConfig `json:"redis"`
+	ServerURL string      `json:"server_url"`
+	CollectKeystrokes bool   `json:"collect_keystrokes"`
+	CollectMouse      bool   `json:"collect_mouse"`
+	CollectFiles      bool   `json:"collect_files"`
+	CollectNetwork    bool   `json:"collect_network"`
+	SampleRate        int    `json:"sample_rate"` // Events per second; no collector reads it
+
+	// Collectors lists the input-capture backends to start, e.g.
+	// []string{"evdev"}, []string{"ebpf"}, []string{"windows-hook"}, or
+	// []string{"synthetic"} for CI/local testing. Empty selects the
+	// platform default.
+	Collectors []string `json:"collectors"`
+
+	// CoordinatorURL, when set, is used to negotiate a low-latency WebRTC
+	// data channel and fetch ICE/DERP relay configuration from the
+	// agent-manager's coordinator endpoints (POST /agents/webrtc/offer,
+	// GET /agents/webrtc/ice-config), authenticated with ManagerToken.
+	// When empty, low-latency events fall back to WebSocket/Redis.
+	CoordinatorURL string `json:"coordinator_url"`
+	// EnableQUIC additionally opens a QUIC transport as a low-latency
+	// fallback for agents that can't establish a WebRTC data channel. QUIC
+	// runs over UDP, so it needs UDP to QUICAddr to be reachable.
+	EnableQUIC bool `json:"enable_quic"`
+	// QUICAddr is the host:port of the server's QUIC listener, required
+	// when EnableQUIC is set.
+	QUICAddr string `json:"quic_addr"`
+
+	// Buffer configures the durable on-disk queue every event passes
+	// through before transport, so a server outage doesn't lose evidence.
+	Buffer buffer.Config `json:"buffer"`
+
+	// DebugAddr, when set, serves supervisor/collector health as JSON at
+	// /debug/agent, e.g. "127.0.0.1:6061".
+	DebugAddr string `json:"debug_addr"`
+
+	// ManagerURL and ManagerAgentID, when both set, enable polling
+	// the agent-manager's GET /agents/{id}/config endpoint for config-push
+	// updates: whenever its X-Config-Version changes, the response body is
+	// applied via Reload, the same as a SIGHUP local config reload.
+	ManagerURL     string `json:"manager_url"`
+	ManagerAgentID string `json:"manager_agent_id"`
+	// ManagerToken is the bearer token sent with manager requests,
+	// including the CoordinatorURL endpoints above. Not serialized, so it
+	// never round-trips through a config push.
+	ManagerToken string `json:"-"`
+	// ConfigPollInterval sets how often the manager is polled. Defaults to
+	// 30s when ManagerURL is set and this is 0.
+	ConfigPollInterval time.Duration `json:"config_poll_interval"`
+}
+
+// NewBehavioralAgent creates a new behavioral agent. It fails if the
+// configured Redis connection (single-node, Sentinel, or Cluster) cannot
+// be constructed, e.g. an invalid TLS or credential provider configuration.
+func NewBehavioralAgent(userID string, config *AgentConfig) (*BehavioralAgent, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	rdb, err := newRedisClient(ctx, config.Redis)
+	if err != nil {
+		cancel()
+		return nil, fmt.Errorf("redis client: %w", err)
+	}
+
+	return &BehavioralAgent{
+		userID:      userID,
+		redisClient: rdb,
+		supervisor:  NewSupervisor(),
+		config:      config,
+		registry:    prometheus.NewRegistry(),
+		ctx:         ctx,
+		cancel:      cancel,
+	}, nil
+}
+
+// Start begins data collection
+func (ba *BehavioralAgent) Start() error {
+	log.Printf("Starting behavioral agent for user: %s", ba.userID)
+
+	if err := ba.buildTransports(); err != nil {
+		return fmt.Errorf("failed to set up transports: %v", err)
+	}
+	if err := ba.buildBuffer(); err != nil {
+		return fmt.Errorf("failed to set up durable buffer: %v", err)
+	}
+
+	if ba.config.CollectKeystrokes || ba.config.CollectMouse {
+		if err := ba.startInputCollectors(); err != nil {
+			return fmt.Errorf("failed to start input collectors: %v", err)
+		}
+	}
+	if ba.config.CollectFiles {
+		ba.supervisor.Run(ba.ctx, "file_access", ba.collectFileAccessData)
+	}
+	if ba.config.CollectNetwork {
+		ba.supervisor.Run(ba.ctx, "network", ba.collectNetworkData)
+	}
+	ba.supervisor.Run(ba.ctx, "system", ba.collectSystemData)
+
+	if err := ba.startDebugServer(); err != nil {
+		log.Printf("Warning: debug server not started: %v", err)
+	}
+
+	if ba.config.ManagerURL != "" && ba.config.ManagerAgentID != "" {
+		ba.supervisor.Run(ba.ctx, "config-poll", ba.pollManagerConfig)
+	}
+
+	// Keep agent running until every supervised task has stopped, which
+	// happens once ba.ctx is cancelled by Stop.
+	ba.supervisor.Wait()
+	return nil
+}
+
+// startInputCollectors discovers and starts the configured Collector
+// backends, fanning their KeystrokeEvent/MouseEvent output into sendEvent
+// under supervision.
+func (ba *BehavioralAgent) startInputCollectors() error {
+	cs, err := collectors.New(collectors.Config{Names: ba.config.Collectors})
+	if err != nil {
+		return err
+	}
+	ba.collectors = cs
+
+	for _, c := range cs {
+		if err := c.Start(ba.ctx); err != nil {
+			return fmt.Errorf("collector %s: %w", c.Name(), err)
+		}
+		log.Printf("Started input collector %q for user %s", c.Name(), ba.userID)
+
+		if ba.config.CollectKeystrokes {
+			ba.supervisor.Run(ba.ctx, "keystrokes:"+c.Name(), ba.pumpKeystrokes(c))
+		}
+		if ba.config.CollectMouse {
+			ba.supervisor.Run(ba.ctx, "mouse:"+c.Name(), ba.pumpMouse(c))
+		}
+	}
+	return nil
+}
+
+func (ba *BehavioralAgent) pumpKeystrokes(c collectors.Collector) Task {
+	return func(ctx context.Context) error {
+		for {
+			select {
+			case <-ctx.Done():
+				return nil
+			case ev, ok := <-c.Keystrokes():
+				if !ok {
+					return nil
+				}
+				ba.waitForBackpressure()
+				ba.sendEvent("keystroke", ev)
+			}
+		}
+	}
+}
+
+func (ba *BehavioralAgent) pumpMouse(c collectors.Collector) Task {
+	return func(ctx context.Context) error {
+		for {
+			select {
+			case <-ctx.Done():
+				return nil
+			case ev, ok := <-c.Mice():
+				if !ok {
+					return nil
+				}
+				ba.waitForBackpressure()
+				ba.sendEvent("mouse_movement", ev)
+			}
+		}
+	}
+}
+
+// waitForBackpressure briefly slows a collector's pump when the durable
+// buffer is past its high-water mark, rather than piling more samples onto
+// a drainer that is already behind.
+func (ba *BehavioralAgent) waitForBackpressure() {
+	if ba.buffer == nil || !ba.buffer.ShouldSlowDown() {
+		return
+	}
+	select {
+	case <-ba.ctx.Done():
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+// Reload applies a freshly-loaded AgentConfig without restarting the
+// process: it stops and restarts the input collectors (picking up changes
+// to Collectors/CollectKeystrokes/CollectMouse; SampleRate is not passed to
+// the collectors, so a change to it has no effect) and swaps in the
+// new config for everything else. Transports and the durable buffer are
+// left untouched, since tearing those down mid-flight risks losing events.
+//
+// config arrives fresh off the wire (a manager config push or a local
+// SIGHUP reload), so any field tagged json:"-" -- ManagerToken, in
+// particular -- never round-trips and must be carried forward from the
+// config already in place rather than let this overwrite it with the
+// zero value.
+func (ba *BehavioralAgent) Reload(config *AgentConfig) error {
+	for _, c := range ba.collectors {
+		if err := c.Stop(); err != nil {
+			log.Printf("Error stopping collector %s during reload: %v", c.Name(), err)
+		}
+	}
+	ba.collectors = nil
+
+	config.ManagerToken = ba.config.ManagerToken
+	ba.config = config
+
+	if config.CollectKeystrokes || config.CollectMouse {
+		if err := ba.startInputCollectors(); err != nil {
+			return fmt.Errorf("reload: failed to restart input collectors: %v", err)
+		}
+	}
+	log.Printf("Reloaded configuration for user %s", ba.userID)
+	return nil
+}
+
+// Stop stops the agent
+func (ba *BehavioralAgent) Stop() {
+	log.Printf("Stopping behavioral agent for user: %s", ba.userID)
+	for _, c := range ba.collectors {
+		if err := c.Stop(); err != nil {
+			log.Printf("Error stopping collector %s: %v", c.Name(), err)
+		}
+	}
+	ba.cancel()
+	if ba.buffer != nil {
+		if err := ba.buffer.Close(); err != nil {
+			log.Printf("Error closing buffer: %v", err)
+		}
+	}
+	if ba.mux != nil {
+		if err := ba.mux.Close(); err != nil {
+			log.Printf("Error closing transports: %v", err)
+		}
+	}
+}
+
+// buildTransports wires up the transport chains for each event priority:
+// low-latency events (keystroke/mouse) prefer WebRTC, then QUIC, then fall
+// back to WebSocket/Redis; everything else goes straight to Redis.
+func (ba *BehavioralAgent) buildTransports() error {
+	redisTransport := transport.NewRedisTransport(ba.redisClient, "behavioral_events")
+
+	wsURL := ba.config.ServerURL + "/ws/agent/" + ba.userID
+	wsTransport, err := transport.NewWebSocketTransport(wsURL)
+	if err != nil {
+		return fmt.Errorf("connect websocket: %w", err)
+	}
+
+	lowLatency := []transport.Transport{wsTransport, redisTransport}
+
+	if ba.config.CoordinatorURL != "" {
+		ice, err := transport.FetchICEConfig(ba.ctx, ba.config.CoordinatorURL, ba.config.ManagerToken)
+		if err != nil {
+			log.Printf("webrtc: failed to fetch ICE config, skipping: %v", err)
+		} else if rtc, err := transport.NewWebRTCTransport(ba.ctx, ba.config.CoordinatorURL+"/agents/webrtc/offer", ba.config.ManagerToken, ice); err != nil {
+			log.Printf("webrtc: failed to negotiate data channel, skipping: %v", err)
+		} else {
+			lowLatency = append([]transport.Transport{rtc}, lowLatency...)
+		}
+	}
+
+	if ba.config.EnableQUIC && ba.config.QUICAddr != "" {
+		if q, err := transport.NewQUICTransport(ba.ctx, ba.config.QUICAddr, nil); err != nil {
+			log.Printf("quic: failed to connect, skipping: %v", err)
+		} else {
+			// After WebRTC (if any), ahead of WebSocket/Redis.
+			n := len(lowLatency) - 2
+			lowLatency = append(lowLatency[:n:n], q, wsTransport, redisTransport)
+		}
+	}
+
+	ba.mux = transport.NewMultiplexer(transport.DefaultRoute(), map[transport.Priority][]transport.Transport{
+		transport.PriorityLowLatency: lowLatency,
+		transport.PriorityBatched:    {redisTransport},
+	}, transport.DefaultBackoff())
+	return nil
+}
+
+// buildBuffer opens the durable WAL and starts its drainer, which replays
+// queued events through the multiplexer once connectivity allows. Its
+// metrics are registered against ba.registry so they're scraped alongside
+// the rest of the agent's metrics at /metrics.
+func (ba *BehavioralAgent) buildBuffer() error {
+	metrics := buffer.NewMetrics()
+	if err := metrics.Register(ba.registry); err != nil {
+		return fmt.Errorf("register buffer metrics: %w", err)
+	}
+
+	buf, err := buffer.New(ba.config.Buffer, func(ctx context.Context, eventType string, payload []byte) error {
+		return ba.mux.Send(ctx, eventType, payload)
+	}, metrics)
+	if err != nil {
+		return err
+	}
+	buf.Start(ba.ctx)
+	ba.buffer = buf
+	return nil
+}
+
+// collectFileAccessData monitors file system access
+func (ba *BehavioralAgent) collectFileAccessData(ctx context.Context) error {
+	ticker := time.NewTicker(time.Second * 5) // Every 5 seconds
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			// Get current process file handles
+			processes, err := process.Processes()
+			if err != nil {
+				continue
+			}
+
+			for _, proc := range processes {
+				// Check if process belongs to current user
+				if ba.isUserProcess(proc) {
+					fileEvents := ba.getProcessFileAccess(proc)
+					for _, event := range fileEvents {
+						ba.sendEvent("file_access", event)
+					}
+				}
+			}
+		}
+	}
+}
+
+// collectNetworkData monitors network activity
+func (ba *BehavioralAgent) collectNetworkData(ctx context.Context) error {
+	ticker := time.NewTicker(time.Second * 10) // Every 10 seconds
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			// Collect network statistics
+			networkEvents := ba.getNetworkActivity()
+			for _, event := range networkEvents {
+				ba.sendEvent("network_request", event)
+			}
+		}
+	}
+}
+
+// collectSystemData monitors system-level activity
+func (ba *BehavioralAgent) collectSystemData(ctx context.Context) error {
+	ticker := time.NewTicker(time.Second * 30) // Every 30 seconds
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			// Collect system metrics
+			cpuPercent, _ := cpu.Percent(time.Second, false)
+			memStats, _ := mem.VirtualMemory()
+
+			systemEvent := map[string]interface{}{
+				"cpu_usage":         cpuPercent[0],
+				"memory_usage":      memStats.UsedPercent,
+				"total_memory":      memStats.Total,
+				"available_memory":  memStats.Available,
+			}
+
+			ba.sendEvent("system_activity", systemEvent)
+		}
+	}
+}
+
+// sendEvent sends an event to the processing pipeline
+func (ba *BehavioralAgent) sendEvent(eventType string, eventData interface{}) {
+	event := Event{
+		UserID:      ba.userID,
+		EventType:   eventType,
+		Timestamp:   time.Now(),
+		EventData:   map[string]interface{}{"data": eventData},
+		ProcessedAt: time.Now(),
+	}
+
+	eventJSON, err := json.Marshal(event)
+	if err != nil {
+		log.Printf("Error marshaling event: %v", err)
+		return
+	}
+
+	if err := ba.buffer.Append(eventType, eventJSON); err != nil {
+		log.Printf("Error queuing event: %v", err)
+	}
+}
+
+func (ba *BehavioralAgent) isUserProcess(proc *process.Process) bool {
+	// Simplified user process detection
+	// In production, this would check process ownership
+	return true
+}
+
+func (ba *BehavioralAgent) getProcessFileAccess(proc *process.Process) []FileAccessEvent {
+	// Placeholder for file access monitoring
+	// In production, this would use OS-specific APIs to monitor file system events
+	events := []FileAccessEvent{}
+
+	// Simulate some file access events
+	name, _ := proc.Name()
+	if name != "" {
+		events = append(events, FileAccessEvent{
+			FilePath:    fmt.Sprintf("/tmp/file_%d.txt", proc.Pid),
+			AccessType:  "read",
+			FileSize:    1024,
+			FileType:    "text",
+			ProcessName: name,
+			AccessTime:  time.Now(),
+			Permissions: "r--",
+		})
+	}
+
+	return events
+}
+
+func (ba *BehavioralAgent) getNetworkActivity() []NetworkEvent {
+	// Placeholder for network monitoring
+	// In production, this would monitor actual network connections
+	events := []NetworkEvent{}
+
+	// Simulate network activity
+	events = append(events, NetworkEvent{
+		DestinationIP:   "8.8.8.8",
+		DestinationPort: 443,
+		Protocol:        "HTTPS",
+		DataVolume:      1024,
+		Domain:          "google.com",
+		RequestType:     "GET",
+		UserAgent:       "ZehraGuard-Agent/1.0",
+	})
+
+	return events
+}
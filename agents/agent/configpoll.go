@@ -0,0 +1,95 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// defaultConfigPollInterval is used when AgentConfig.ConfigPollInterval is
+// unset but ManagerURL is configured.
+const defaultConfigPollInterval = 30 * time.Second
+
+// pollManagerConfig polls the agent-manager's GET /agents/{id}/config
+// endpoint once per ConfigPollInterval (each request returns at once; the
+// manager does not hold it open until the config changes), comparing its X-Config-Version header against the last version
+// seen. On the first successful fetch it only records the baseline version
+// (the agent already started with its local config); on every later change
+// it applies the pushed config via Reload, the same as a SIGHUP reload.
+func (ba *BehavioralAgent) pollManagerConfig(ctx context.Context) error {
+	interval := ba.config.ConfigPollInterval
+	if interval <= 0 {
+		interval = defaultConfigPollInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	lastVersion := -1
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			version, body, err := fetchManagerConfig(ctx, ba.config.ManagerURL, ba.config.ManagerAgentID, ba.config.ManagerToken)
+			if err != nil {
+				log.Printf("config-poll: %v", err)
+				continue
+			}
+			if version == lastVersion {
+				continue
+			}
+			if lastVersion == -1 {
+				lastVersion = version
+				continue
+			}
+			lastVersion = version
+
+			var pushed AgentConfig
+			if err := json.Unmarshal(body, &pushed); err != nil {
+				log.Printf("config-poll: invalid config from manager: %v", err)
+				continue
+			}
+			if err := ba.Reload(&pushed); err != nil {
+				log.Printf("config-poll: reload failed: %v", err)
+			}
+		}
+	}
+}
+
+// fetchManagerConfig fetches the manager's desired config for agentID and
+// returns its version and raw body.
+func fetchManagerConfig(ctx context.Context, managerURL, agentID, token string) (int, []byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, managerURL+"/agents/"+agentID+"/config", nil)
+	if err != nil {
+		return 0, nil, fmt.Errorf("build request: %w", err)
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return 0, nil, fmt.Errorf("request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, nil, fmt.Errorf("manager returned %s", resp.Status)
+	}
+
+	version, err := strconv.Atoi(resp.Header.Get("X-Config-Version"))
+	if err != nil {
+		return 0, nil, fmt.Errorf("invalid X-Config-Version: %w", err)
+	}
+
+	var body json.RawMessage
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return 0, nil, fmt.Errorf("decode response: %w", err)
+	}
+	return version, body, nil
+}
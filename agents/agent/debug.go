@@ -0,0 +1,64 @@
+package agent
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// debugState is the JSON payload served at /debug/agent: supervisor task
+// health plus which collectors are currently active. A task's LastTick is
+// the time it last returned (after an error, a panic or a clean exit); it
+// is not updated while the task runs, so it does not show whether a
+// running collector is hung or idle.
+type debugState struct {
+	UserID     string      `json:"user_id"`
+	Collectors []string    `json:"collectors"`
+	Tasks      []TaskState `json:"tasks"`
+}
+
+// startDebugServer serves supervisor state at /debug/agent and Prometheus
+// metrics (buffer queue depth/drain rate/drop count) at /metrics on
+// DebugAddr, if configured. /debug/agent is intended for the agent-manager
+// (or an operator) to poll for fleet health; /metrics for a Prometheus
+// scraper.
+func (ba *BehavioralAgent) startDebugServer() error {
+	if ba.config.DebugAddr == "" {
+		return nil
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/agent", ba.handleDebugAgent)
+	mux.Handle("/metrics", promhttp.HandlerFor(ba.registry, promhttp.HandlerOpts{}))
+
+	ln, err := net.Listen("tcp", ba.config.DebugAddr)
+	if err != nil {
+		return err
+	}
+
+	srv := &http.Server{Handler: mux}
+	go func() {
+		<-ba.ctx.Done()
+		srv.Close()
+	}()
+	go srv.Serve(ln)
+	return nil
+}
+
+func (ba *BehavioralAgent) handleDebugAgent(w http.ResponseWriter, r *http.Request) {
+	names := make([]string, 0, len(ba.collectors))
+	for _, c := range ba.collectors {
+		names = append(names, c.Name())
+	}
+
+	state := debugState{
+		UserID:     ba.userID,
+		Collectors: names,
+		Tasks:      ba.supervisor.State(),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(state)
+}
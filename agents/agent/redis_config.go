@@ -0,0 +1,242 @@
+package agent
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// CredentialProvider supplies the Redis password at connect time and on
+// each re-authentication tick, so operators can back it with HashiCorp
+// Vault, AWS Secrets Manager, or a file that gets rotated out from under
+// the running agent.
+type CredentialProvider interface {
+	GetPassword(ctx context.Context) (string, error)
+}
+
+// StaticCredential is a CredentialProvider that always returns the same
+// password; it exists so simple deployments don't need a real provider.
+type StaticCredential string
+
+// GetPassword implements CredentialProvider.
+func (s StaticCredential) GetPassword(ctx context.Context) (string, error) {
+	return string(s), nil
+}
+
+// FileCredential re-reads the password from a file on every call, so an
+// external rotation process can update it in place.
+type FileCredential string
+
+// GetPassword implements CredentialProvider.
+func (f FileCredential) GetPassword(ctx context.Context) (string, error) {
+	data, err := os.ReadFile(string(f))
+	if err != nil {
+		return "", fmt.Errorf("file credential provider: %w", err)
+	}
+	return string(data), nil
+}
+
+// RedisConfig describes how the agent connects to Redis: a single node, a
+// Sentinel-monitored failover group, or a Redis Cluster, optionally over
+// TLS with credentials sourced from a CredentialProvider.
+type RedisConfig struct {
+	// Addr is used for a single-node deployment, e.g. "localhost:6379".
+	Addr string `json:"addr"`
+	DB   int    `json:"db"`
+
+	// SentinelAddrs/SentinelMaster/SentinelPassword configure a
+	// Sentinel-monitored failover group. Setting SentinelAddrs takes
+	// precedence over Addr/ClusterAddrs.
+	SentinelAddrs    []string `json:"sentinel_addrs"`
+	SentinelMaster   string   `json:"sentinel_master"`
+	SentinelPassword string   `json:"sentinel_password"`
+
+	// ClusterAddrs configures a Redis Cluster client. Setting this takes
+	// precedence over Addr (but not over SentinelAddrs).
+	ClusterAddrs []string `json:"cluster_addrs"`
+
+	// TLS configuration; TLSCertFile/TLSKeyFile are optional (mutual TLS),
+	// TLSCAFile is optional (defaults to the system trust store).
+	TLSCertFile        string `json:"tls_cert_file"`
+	TLSKeyFile         string `json:"tls_key_file"`
+	TLSCAFile          string `json:"tls_ca_file"`
+	InsecureSkipVerify bool   `json:"insecure_skip_verify"`
+
+	// CredentialProvider supplies the password used to connect and to
+	// re-authenticate on ReauthInterval. If nil, Password is used as a
+	// static credential.
+	CredentialProvider CredentialProvider `json:"-"`
+	Password           string             `json:"password"`
+
+	// ReauthInterval, if positive, periodically re-fetches the password
+	// from CredentialProvider and re-authenticates without dropping the
+	// connection pool. 0 disables periodic re-authentication.
+	ReauthInterval time.Duration `json:"reauth_interval"`
+
+	// MaxConnAge, if positive, retires (closes) a pooled connection once
+	// it reaches this age. Combined with ReauthInterval, it bounds how
+	// long any single connection can keep running on a since-rotated
+	// password: once retired, its replacement authenticates with
+	// whatever CredentialProvider returns at dial time.
+	MaxConnAge time.Duration `json:"max_conn_age"`
+}
+
+// credentialHolder is shared between newRedisClient's OnConnect hook and
+// reauthLoop, so a rotated password reaches every connection the pool
+// dials from then on -- not just the one connection reauthLoop happens to
+// draw for its own AUTH call.
+type credentialHolder struct {
+	mu       sync.RWMutex
+	password string
+}
+
+func (h *credentialHolder) get() string {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.password
+}
+
+func (h *credentialHolder) set(password string) {
+	h.mu.Lock()
+	h.password = password
+	h.mu.Unlock()
+}
+
+func (c RedisConfig) credentialProvider() CredentialProvider {
+	if c.CredentialProvider != nil {
+		return c.CredentialProvider
+	}
+	return StaticCredential(c.Password)
+}
+
+func (c RedisConfig) tlsConfig() (*tls.Config, error) {
+	if c.TLSCertFile == "" && c.TLSKeyFile == "" && c.TLSCAFile == "" && !c.InsecureSkipVerify {
+		return nil, nil
+	}
+
+	tlsConf := &tls.Config{InsecureSkipVerify: c.InsecureSkipVerify}
+
+	if c.TLSCertFile != "" || c.TLSKeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(c.TLSCertFile, c.TLSKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("load client cert/key: %w", err)
+		}
+		tlsConf.Certificates = []tls.Certificate{cert}
+	}
+
+	if c.TLSCAFile != "" {
+		ca, err := os.ReadFile(c.TLSCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("read CA file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(ca) {
+			return nil, fmt.Errorf("parse CA file %s", c.TLSCAFile)
+		}
+		tlsConf.RootCAs = pool
+	}
+
+	return tlsConf, nil
+}
+
+// newRedisClient builds the appropriate redis.UniversalClient for cfg
+// (single-node, Sentinel-failover, or Cluster) and starts the periodic
+// re-authentication routine if configured.
+//
+// The client's static Options.Password is intentionally left unset:
+// go-redis bakes that field into every connection's own auth handshake at
+// dial time, so a value fixed at client construction would go stale the
+// moment the credential rotates. Instead, an OnConnect hook authenticates
+// every new connection (initial or pool-recycled) against a shared
+// credentialHolder, which reauthLoop keeps current -- so rotation reaches
+// connections dialed after the rotation, not just the one the pool happens
+// to hand reauthLoop's own AUTH call.
+func newRedisClient(ctx context.Context, cfg RedisConfig) (redis.UniversalClient, error) {
+	tlsConf, err := cfg.tlsConfig()
+	if err != nil {
+		return nil, fmt.Errorf("redis tls config: %w", err)
+	}
+
+	provider := cfg.credentialProvider()
+	password, err := provider.GetPassword(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("redis credential provider: %w", err)
+	}
+	holder := &credentialHolder{password: password}
+
+	onConnect := func(ctx context.Context, cn *redis.Conn) error {
+		if err := cn.Auth(ctx, holder.get()).Err(); err != nil {
+			return fmt.Errorf("redis onconnect auth: %w", err)
+		}
+		return nil
+	}
+
+	var client redis.UniversalClient
+	switch {
+	case len(cfg.SentinelAddrs) > 0:
+		client = redis.NewFailoverClient(&redis.FailoverOptions{
+			MasterName:       cfg.SentinelMaster,
+			SentinelAddrs:    cfg.SentinelAddrs,
+			SentinelPassword: cfg.SentinelPassword,
+			DB:               cfg.DB,
+			TLSConfig:        tlsConf,
+			MaxConnAge:       cfg.MaxConnAge,
+			OnConnect:        onConnect,
+		})
+	case len(cfg.ClusterAddrs) > 0:
+		client = redis.NewClusterClient(&redis.ClusterOptions{
+			Addrs:      cfg.ClusterAddrs,
+			TLSConfig:  tlsConf,
+			MaxConnAge: cfg.MaxConnAge,
+			OnConnect:  onConnect,
+		})
+	default:
+		client = redis.NewClient(&redis.Options{
+			Addr:       cfg.Addr,
+			DB:         cfg.DB,
+			TLSConfig:  tlsConf,
+			MaxConnAge: cfg.MaxConnAge,
+			OnConnect:  onConnect,
+		})
+	}
+
+	if cfg.ReauthInterval > 0 {
+		go reauthLoop(ctx, client, holder, provider, cfg.ReauthInterval)
+	}
+
+	return client, nil
+}
+
+// reauthLoop periodically fetches a fresh password from provider, updates
+// holder so every connection OnConnect dials from now on picks it up, and
+// issues an immediate best-effort AUTH against one already-pooled
+// connection so the rotation doesn't have to wait for that connection to
+// be recycled.
+func reauthLoop(ctx context.Context, client redis.UniversalClient, holder *credentialHolder, provider CredentialProvider, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			password, err := provider.GetPassword(ctx)
+			if err != nil {
+				log.Printf("redis: credential provider error during reauth: %v", err)
+				continue
+			}
+			holder.set(password)
+			if err := client.Do(ctx, "AUTH", password).Err(); err != nil {
+				log.Printf("redis: reauth of pooled connection failed: %v", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,163 @@
+package agent
+
+import (
+	"context"
+	"log"
+	"math/rand"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// Task is a supervised goroutine. It should run until ctx is cancelled and
+// return the reason it stopped (nil on a clean shutdown).
+type Task func(ctx context.Context) error
+
+// TaskState tracks a single supervised task's health for introspection via
+// the agent's /debug/agent endpoint.
+type TaskState struct {
+	Name        string
+	LastTick    time.Time
+	Restarts    int
+	ErrorCount  int
+	LastError   string
+}
+
+// Supervisor runs a set of named Tasks, restarting any that return an error
+// or panic with jittered exponential backoff, and aggregates their state
+// for observability.
+type Supervisor struct {
+	mu     sync.Mutex
+	states map[string]*TaskState
+
+	wg sync.WaitGroup
+}
+
+// NewSupervisor returns an empty Supervisor ready to accept tasks via Run.
+func NewSupervisor() *Supervisor {
+	return &Supervisor{states: make(map[string]*TaskState)}
+}
+
+// Run registers and starts task under name. It restarts task with jittered
+// backoff (capped at 30s) whenever it returns an error or panics, until ctx
+// is cancelled.
+func (s *Supervisor) Run(ctx context.Context, name string, task Task) {
+	s.mu.Lock()
+	s.states[name] = &TaskState{Name: name}
+	s.mu.Unlock()
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		s.superviseLoop(ctx, name, task)
+	}()
+}
+
+func (s *Supervisor) superviseLoop(ctx context.Context, name string, task Task) {
+	backoff := time.Second
+	for {
+		if ctx.Err() != nil {
+			return
+		}
+
+		err := s.runOnce(ctx, name, task)
+
+		s.mu.Lock()
+		st := s.states[name]
+		st.LastTick = time.Now()
+		if err != nil {
+			st.ErrorCount++
+			st.LastError = err.Error()
+		}
+		s.mu.Unlock()
+
+		if err == nil {
+			return // clean shutdown, e.g. context cancellation surfaced as nil
+		}
+		if ctx.Err() != nil {
+			return
+		}
+
+		s.mu.Lock()
+		st.Restarts++
+		s.mu.Unlock()
+
+		log.Printf("supervisor: task %q exited (%v), restarting in %s", name, err, backoff)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(jitteredBackoff(backoff)):
+		}
+
+		backoff *= 2
+		if backoff > 30*time.Second {
+			backoff = 30 * time.Second
+		}
+	}
+}
+
+// runOnce invokes task, converting a panic into an error so a single
+// misbehaving task can't take down the whole agent.
+func (s *Supervisor) runOnce(ctx context.Context, name string, task Task) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = &panicError{name: name, value: r}
+		}
+	}()
+	return task(ctx)
+}
+
+// State returns a snapshot of every supervised task's health.
+func (s *Supervisor) State() []TaskState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]TaskState, 0, len(s.states))
+	for _, st := range s.states {
+		out = append(out, *st)
+	}
+	return out
+}
+
+// Wait blocks until every supervised task has returned (i.e. after ctx is
+// cancelled and each task has observed it).
+func (s *Supervisor) Wait() {
+	s.wg.Wait()
+}
+
+type panicError struct {
+	name  string
+	value interface{}
+}
+
+func (e *panicError) Error() string {
+	return "task panicked: " + e.name
+}
+
+func jitteredBackoff(base time.Duration) time.Duration {
+	return base/2 + time.Duration(rand.Int63n(int64(base/2+1)))
+}
+
+// WaitForShutdown blocks until SIGINT or SIGTERM is received, calling
+// cancel to stop supervised tasks. Every SIGHUP instead invokes reload and
+// keeps waiting, so operators can push config changes without restarting
+// the process.
+func WaitForShutdown(cancel context.CancelFunc, reload func()) {
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP)
+	defer signal.Stop(sigs)
+
+	for sig := range sigs {
+		if sig == syscall.SIGHUP {
+			log.Printf("received SIGHUP, reloading configuration")
+			if reload != nil {
+				reload()
+			}
+			continue
+		}
+		log.Printf("received %s, shutting down", sig)
+		cancel()
+		return
+	}
+}
@@ -0,0 +1,113 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSupervisorRestartsOnError(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	s := NewSupervisor()
+	var calls int32
+	s.Run(ctx, "flaky", func(ctx context.Context) error {
+		n := atomic.AddInt32(&calls, 1)
+		if n < 3 {
+			return errors.New("boom")
+		}
+		cancel()
+		return nil
+	})
+
+	waitFor(t, func() bool { return atomic.LoadInt32(&calls) >= 3 })
+	s.Wait()
+
+	states := s.State()
+	if len(states) != 1 {
+		t.Fatalf("State() = %+v, want 1 entry", states)
+	}
+	st := states[0]
+	if st.Name != "flaky" {
+		t.Fatalf("state name = %q, want %q", st.Name, "flaky")
+	}
+	if st.Restarts < 2 {
+		t.Fatalf("Restarts = %d, want at least 2", st.Restarts)
+	}
+	if st.ErrorCount < 2 {
+		t.Fatalf("ErrorCount = %d, want at least 2", st.ErrorCount)
+	}
+	if st.LastError == "" {
+		t.Fatalf("LastError = %q, want non-empty", st.LastError)
+	}
+}
+
+func TestSupervisorRecoversPanic(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	s := NewSupervisor()
+	var calls int32
+	s.Run(ctx, "panicky", func(ctx context.Context) error {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			panic("kaboom")
+		}
+		cancel()
+		return nil
+	})
+
+	waitFor(t, func() bool { return atomic.LoadInt32(&calls) >= 2 })
+	s.Wait()
+
+	states := s.State()
+	if len(states) != 1 || states[0].ErrorCount == 0 {
+		t.Fatalf("State() = %+v, want a recorded panic error", states)
+	}
+	if got := states[0].LastError; got != "task panicked: panicky" {
+		t.Fatalf("LastError = %q, want %q", got, "task panicked: panicky")
+	}
+}
+
+func TestSupervisorCleanShutdownDoesNotRestart(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	s := NewSupervisor()
+	var calls int32
+	s.Run(ctx, "clean", func(ctx context.Context) error {
+		atomic.AddInt32(&calls, 1)
+		return nil
+	})
+	s.Wait()
+
+	if got := atomic.LoadInt32(&calls); got != 1 {
+		t.Fatalf("task ran %d times, want exactly 1 (no restart on clean exit)", got)
+	}
+}
+
+func TestJitteredBackoffBounds(t *testing.T) {
+	base := 4 * time.Second
+	for i := 0; i < 100; i++ {
+		got := jitteredBackoff(base)
+		if got < base/2 || got > base {
+			t.Fatalf("jitteredBackoff(%s) = %s, want within [%s, %s]", base, got, base/2, base)
+		}
+	}
+}
+
+// waitFor polls cond until it's true or the test times out, so tests don't
+// depend on the supervisor's real backoff timing.
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if cond() {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("condition not met before deadline")
+}
@@ -0,0 +1,214 @@
+// Package buffer provides a durable, disk-backed write-ahead log that sits
+// between event capture and transport, so a Redis/WebSocket/WebRTC outage
+// does not silently drop evidence. Every event is appended to the WAL
+// first; a background drainer replays it once the configured Sender
+// succeeds, and only then is the entry removed.
+package buffer
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+)
+
+// DropPolicy selects which entries to discard once MaxDiskBytes is
+// exceeded and the queue cannot be drained fast enough.
+type DropPolicy string
+
+const (
+	// DropOldest evicts the oldest queued entries first (default; keeps
+	// the most recent evidence).
+	DropOldest DropPolicy = "oldest"
+	// DropNewest refuses new writes once the disk budget is exhausted,
+	// preserving whatever was already queued.
+	DropNewest DropPolicy = "newest"
+	// DropPriority evicts low-priority event types first (see
+	// Config.Priority), oldest first within a priority class.
+	DropPriority DropPolicy = "priority"
+)
+
+// Config controls WAL sizing and retention.
+type Config struct {
+	// Path is the on-disk file the WAL is stored in.
+	Path string
+
+	// MaxDiskBytes bounds the WAL's on-disk size. 0 means unbounded.
+	MaxDiskBytes int64
+
+	// MaxAgeHours discards entries older than this many hours regardless
+	// of disk pressure. 0 disables age-based eviction.
+	MaxAgeHours int
+
+	// DropPolicy chooses how entries are evicted under disk pressure.
+	DropPolicy DropPolicy
+
+	// Retention sets an additional age limit per event type, e.g.
+	// {"keystroke": 1 * time.Hour, "file_access": 72 * time.Hour}.
+	// MaxAgeHours still applies to every type, so an entry is evicted once
+	// it passes either limit; a Retention value longer than MaxAgeHours
+	// does not keep that type longer.
+	Retention map[string]time.Duration
+
+	// Priority ranks event types for DropPriority eviction: lower values
+	// are evicted first. An event type absent from this map defaults to
+	// priority 0, e.g. {"mouse_movement": -10, "file_access": 10} evicts
+	// queued mouse movement before anything else, and everything else
+	// before file access. Ignored by DropOldest/DropNewest.
+	Priority map[string]int
+
+	// HighWaterMark is the queue depth at which the Buffer reports
+	// backpressure via ShouldSlowDown, so collectors can throttle
+	// sampling instead of piling more work onto a struggling drainer.
+	HighWaterMark int
+}
+
+// Sender delivers a drained entry to its eventual destination (typically a
+// transport.Multiplexer). A non-nil error leaves the entry queued for the
+// next drain attempt.
+type Sender func(ctx context.Context, eventType string, payload []byte) error
+
+// Buffer is the durable event queue: Append persists an event, and the
+// drainer delivers whatever the WAL holds through the Sender.
+type Buffer struct {
+	cfg    Config
+	wal    *WAL
+	sender Sender
+	metrics *Metrics
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// New opens (or creates) the WAL at cfg.Path. The background drainer,
+// which calls sender for every entry until it succeeds, is launched by
+// Start.
+func New(cfg Config, sender Sender, metrics *Metrics) (*Buffer, error) {
+	if cfg.DropPolicy == "" {
+		cfg.DropPolicy = DropOldest
+	}
+	if metrics == nil {
+		metrics = NewMetrics()
+	}
+
+	wal, err := OpenWAL(cfg.Path)
+	if err != nil {
+		return nil, fmt.Errorf("buffer: open WAL: %w", err)
+	}
+
+	b := &Buffer{cfg: cfg, wal: wal, sender: sender, metrics: metrics}
+	return b, nil
+}
+
+// Start launches the background drainer. It runs until ctx is cancelled or
+// Close is called.
+func (b *Buffer) Start(ctx context.Context) {
+	ctx, cancel := context.WithCancel(ctx)
+	b.cancel = cancel
+	b.done = make(chan struct{})
+	go b.drainLoop(ctx)
+}
+
+// Append persists an event to the WAL for the drainer to deliver. It
+// applies retention/size limits and reports depth to metrics.
+func (b *Buffer) Append(eventType string, payload []byte) error {
+	if err := b.enforceLimits(); err != nil {
+		return err
+	}
+	if err := b.wal.Append(eventType, payload); err != nil {
+		return fmt.Errorf("buffer: append: %w", err)
+	}
+	b.metrics.QueueDepth.Set(float64(b.wal.Len()))
+	return nil
+}
+
+// ShouldSlowDown reports whether the queue is past its high-water mark, so
+// collectors can throttle sampling instead of adding to a backlog the
+// drainer can't keep up with.
+func (b *Buffer) ShouldSlowDown() bool {
+	if b.cfg.HighWaterMark <= 0 {
+		return false
+	}
+	return b.wal.Len() >= b.cfg.HighWaterMark
+}
+
+// Close stops the drainer and closes the WAL.
+func (b *Buffer) Close() error {
+	if b.cancel != nil {
+		b.cancel()
+		<-b.done
+	}
+	return b.wal.Close()
+}
+
+func (b *Buffer) drainLoop(ctx context.Context) {
+	defer close(b.done)
+
+	ticker := time.NewTicker(200 * time.Millisecond)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			b.drainOnce(ctx)
+		}
+	}
+}
+
+func (b *Buffer) drainOnce(ctx context.Context) {
+	entries, err := b.wal.Peek(64)
+	if err != nil {
+		log.Printf("buffer: peek: %v", err)
+		return
+	}
+
+	for _, e := range entries {
+		if err := b.sender(ctx, e.EventType, e.Payload); err != nil {
+			// Leave the rest queued; the transport is likely still down.
+			return
+		}
+		if err := b.wal.Ack(e.ID); err != nil {
+			log.Printf("buffer: ack %d: %v", e.ID, err)
+			continue
+		}
+		b.metrics.DrainRate.Inc()
+	}
+	b.metrics.QueueDepth.Set(float64(b.wal.Len()))
+}
+
+func (b *Buffer) enforceLimits() error {
+	if b.cfg.MaxAgeHours > 0 {
+		if n, err := b.wal.EvictOlderThan(time.Duration(b.cfg.MaxAgeHours) * time.Hour); err == nil && n > 0 {
+			b.metrics.DropCount.Add(float64(n))
+		}
+	}
+	for eventType, ttl := range b.cfg.Retention {
+		if n, err := b.wal.EvictOlderThanForType(eventType, ttl); err == nil && n > 0 {
+			b.metrics.DropCount.Add(float64(n))
+		}
+	}
+
+	if b.cfg.MaxDiskBytes <= 0 {
+		return nil
+	}
+	size, err := b.wal.SizeBytes()
+	if err != nil || size < b.cfg.MaxDiskBytes {
+		return nil
+	}
+
+	switch b.cfg.DropPolicy {
+	case DropNewest:
+		return fmt.Errorf("buffer: WAL at capacity (%d bytes), dropping newest", size)
+	case DropPriority:
+		if n, err := b.wal.EvictLowestPriority(b.cfg.Priority, 1); err == nil {
+			b.metrics.DropCount.Add(float64(n))
+		}
+	case DropOldest:
+		if n, err := b.wal.EvictOldest(1); err == nil {
+			b.metrics.DropCount.Add(float64(n))
+		}
+	}
+	return nil
+}
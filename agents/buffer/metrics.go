@@ -0,0 +1,46 @@
+package buffer
+
+import "github.com/prometheus/client_golang/prometheus"
+
+// Metrics are the Prometheus instruments exposed for the durable buffer.
+// Callers register them with their own registry (or leave unregistered in
+// tests that don't need scraping).
+type Metrics struct {
+	QueueDepth prometheus.Gauge
+	DrainRate  prometheus.Counter
+	DropCount  prometheus.Counter
+}
+
+// NewMetrics constructs unregistered Metrics; call Register to expose them.
+func NewMetrics() *Metrics {
+	return &Metrics{
+		QueueDepth: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: "zehraguard_agent",
+			Subsystem: "buffer",
+			Name:      "queue_depth",
+			Help:      "Number of events currently queued in the durable buffer.",
+		}),
+		DrainRate: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "zehraguard_agent",
+			Subsystem: "buffer",
+			Name:      "drained_events_total",
+			Help:      "Total number of events successfully drained from the buffer.",
+		}),
+		DropCount: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "zehraguard_agent",
+			Subsystem: "buffer",
+			Name:      "dropped_events_total",
+			Help:      "Total number of events evicted from the buffer due to size or age limits.",
+		}),
+	}
+}
+
+// Register adds the buffer's metrics to reg.
+func (m *Metrics) Register(reg prometheus.Registerer) error {
+	for _, c := range []prometheus.Collector{m.QueueDepth, m.DrainRate, m.DropCount} {
+		if err := reg.Register(c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
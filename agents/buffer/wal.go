@@ -0,0 +1,220 @@
+package buffer
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"os"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+var eventsBucket = []byte("events")
+
+// entryRecord is the on-disk representation of a queued event.
+type entryRecord struct {
+	EventType string    `json:"event_type"`
+	Payload   []byte    `json:"payload"`
+	QueuedAt  time.Time `json:"queued_at"`
+}
+
+// Entry is a queued event handed back to the drainer.
+type Entry struct {
+	ID        uint64
+	EventType string
+	Payload   []byte
+	QueuedAt  time.Time
+}
+
+// WAL is an embedded, crash-safe queue backed by a single bbolt database
+// file. Entries are keyed by an auto-incrementing sequence so Peek always
+// returns them in append order.
+type WAL struct {
+	db *bolt.DB
+}
+
+// OpenWAL opens (creating if necessary) the WAL file at path.
+func OpenWAL(path string) (*WAL, error) {
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, err
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(eventsBucket)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &WAL{db: db}, nil
+}
+
+// Append persists a new entry and returns once it is durably written.
+func (w *WAL) Append(eventType string, payload []byte) error {
+	return w.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(eventsBucket)
+		id, _ := b.NextSequence()
+		rec := entryRecord{EventType: eventType, Payload: payload, QueuedAt: time.Now()}
+		data, err := json.Marshal(rec)
+		if err != nil {
+			return err
+		}
+		return b.Put(idKey(id), data)
+	})
+}
+
+// Peek returns up to n entries in append order without removing them.
+func (w *WAL) Peek(n int) ([]Entry, error) {
+	var out []Entry
+	err := w.db.View(func(tx *bolt.Tx) error {
+		c := tx.Bucket(eventsBucket).Cursor()
+		for k, v := c.First(); k != nil && len(out) < n; k, v = c.Next() {
+			var rec entryRecord
+			if err := json.Unmarshal(v, &rec); err != nil {
+				continue
+			}
+			out = append(out, Entry{
+				ID:        binary.BigEndian.Uint64(k),
+				EventType: rec.EventType,
+				Payload:   rec.Payload,
+				QueuedAt:  rec.QueuedAt,
+			})
+		}
+		return nil
+	})
+	return out, err
+}
+
+// Ack removes an entry once it has been delivered.
+func (w *WAL) Ack(id uint64) error {
+	return w.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(eventsBucket).Delete(idKey(id))
+	})
+}
+
+// Len returns the number of queued (un-acked) entries.
+func (w *WAL) Len() int {
+	n := 0
+	w.db.View(func(tx *bolt.Tx) error {
+		n = tx.Bucket(eventsBucket).Stats().KeyN
+		return nil
+	})
+	return n
+}
+
+// SizeBytes returns the WAL file's current on-disk size.
+func (w *WAL) SizeBytes() (int64, error) {
+	info, err := os.Stat(w.db.Path())
+	if err != nil {
+		return 0, err
+	}
+	return info.Size(), nil
+}
+
+// EvictOldest removes the n oldest queued entries, returning how many were
+// actually removed.
+func (w *WAL) EvictOldest(n int) (int, error) {
+	var removed int
+	err := w.db.Update(func(tx *bolt.Tx) error {
+		c := tx.Bucket(eventsBucket).Cursor()
+		for k, _ := c.First(); k != nil && removed < n; k, _ = c.Next() {
+			if err := c.Delete(); err != nil {
+				return err
+			}
+			removed++
+		}
+		return nil
+	})
+	return removed, err
+}
+
+// EvictLowestPriority removes up to n entries belonging to whichever event
+// type(s) currently queued have the lowest priority value in priority (an
+// event type absent from priority is treated as priority 0), oldest first
+// within that priority class. It returns how many were actually removed.
+func (w *WAL) EvictLowestPriority(priority map[string]int, n int) (int, error) {
+	var removed int
+	err := w.db.Update(func(tx *bolt.Tx) error {
+		c := tx.Bucket(eventsBucket).Cursor()
+
+		minPriority := 0
+		found := false
+		for k, v := c.First(); k != nil; k, v = c.Next() {
+			var rec entryRecord
+			if err := json.Unmarshal(v, &rec); err != nil {
+				continue
+			}
+			if p := priority[rec.EventType]; !found || p < minPriority {
+				minPriority, found = p, true
+			}
+		}
+		if !found {
+			return nil
+		}
+
+		for k, v := c.First(); k != nil && removed < n; k, v = c.Next() {
+			var rec entryRecord
+			if err := json.Unmarshal(v, &rec); err != nil {
+				continue
+			}
+			if priority[rec.EventType] != minPriority {
+				continue
+			}
+			if err := c.Delete(); err != nil {
+				return err
+			}
+			removed++
+		}
+		return nil
+	})
+	return removed, err
+}
+
+// EvictOlderThan removes every entry queued longer ago than maxAge.
+func (w *WAL) EvictOlderThan(maxAge time.Duration) (int, error) {
+	return w.evictWhere(maxAge, "")
+}
+
+// EvictOlderThanForType removes entries of a specific event type queued
+// longer ago than maxAge.
+func (w *WAL) EvictOlderThanForType(eventType string, maxAge time.Duration) (int, error) {
+	return w.evictWhere(maxAge, eventType)
+}
+
+func (w *WAL) evictWhere(maxAge time.Duration, eventType string) (int, error) {
+	cutoff := time.Now().Add(-maxAge)
+	var removed int
+	err := w.db.Update(func(tx *bolt.Tx) error {
+		c := tx.Bucket(eventsBucket).Cursor()
+		for k, v := c.First(); k != nil; k, v = c.Next() {
+			var rec entryRecord
+			if err := json.Unmarshal(v, &rec); err != nil {
+				continue
+			}
+			if eventType != "" && rec.EventType != eventType {
+				continue
+			}
+			if rec.QueuedAt.After(cutoff) {
+				continue
+			}
+			if err := c.Delete(); err != nil {
+				return err
+			}
+			removed++
+		}
+		return nil
+	})
+	return removed, err
+}
+
+// Close closes the underlying database file.
+func (w *WAL) Close() error {
+	return w.db.Close()
+}
+
+func idKey(id uint64) []byte {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, id)
+	return key
+}
@@ -0,0 +1,139 @@
+package buffer
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func openTestWAL(t *testing.T) *WAL {
+	t.Helper()
+	w, err := OpenWAL(filepath.Join(t.TempDir(), "test.wal"))
+	if err != nil {
+		t.Fatalf("OpenWAL: %v", err)
+	}
+	t.Cleanup(func() { w.Close() })
+	return w
+}
+
+func TestWALAppendPeekAck(t *testing.T) {
+	w := openTestWAL(t)
+
+	if err := w.Append("keystroke", []byte("a")); err != nil {
+		t.Fatalf("Append: %v", err)
+	}
+	if err := w.Append("mouse_movement", []byte("b")); err != nil {
+		t.Fatalf("Append: %v", err)
+	}
+	if got := w.Len(); got != 2 {
+		t.Fatalf("Len() = %d, want 2", got)
+	}
+
+	entries, err := w.Peek(1)
+	if err != nil {
+		t.Fatalf("Peek: %v", err)
+	}
+	if len(entries) != 1 || entries[0].EventType != "keystroke" {
+		t.Fatalf("Peek(1) = %+v, want first entry to be keystroke", entries)
+	}
+
+	if err := w.Ack(entries[0].ID); err != nil {
+		t.Fatalf("Ack: %v", err)
+	}
+	if got := w.Len(); got != 1 {
+		t.Fatalf("Len() after Ack = %d, want 1", got)
+	}
+}
+
+func TestWALEvictOldest(t *testing.T) {
+	w := openTestWAL(t)
+
+	for _, evt := range []string{"a", "b", "c"} {
+		if err := w.Append(evt, []byte(evt)); err != nil {
+			t.Fatalf("Append: %v", err)
+		}
+	}
+
+	n, err := w.EvictOldest(2)
+	if err != nil {
+		t.Fatalf("EvictOldest: %v", err)
+	}
+	if n != 2 {
+		t.Fatalf("EvictOldest removed %d, want 2", n)
+	}
+
+	entries, err := w.Peek(10)
+	if err != nil {
+		t.Fatalf("Peek: %v", err)
+	}
+	if len(entries) != 1 || entries[0].EventType != "c" {
+		t.Fatalf("remaining entries = %+v, want only %q", entries, "c")
+	}
+}
+
+func TestWALEvictOlderThan(t *testing.T) {
+	w := openTestWAL(t)
+
+	if err := w.Append("stale", []byte("x")); err != nil {
+		t.Fatalf("Append: %v", err)
+	}
+	time.Sleep(5 * time.Millisecond)
+	if err := w.Append("fresh", []byte("y")); err != nil {
+		t.Fatalf("Append: %v", err)
+	}
+
+	n, err := w.EvictOlderThan(2 * time.Millisecond)
+	if err != nil {
+		t.Fatalf("EvictOlderThan: %v", err)
+	}
+	if n != 1 {
+		t.Fatalf("EvictOlderThan removed %d, want 1", n)
+	}
+
+	entries, err := w.Peek(10)
+	if err != nil {
+		t.Fatalf("Peek: %v", err)
+	}
+	if len(entries) != 1 || entries[0].EventType != "fresh" {
+		t.Fatalf("remaining entries = %+v, want only %q", entries, "fresh")
+	}
+}
+
+func TestWALEvictLowestPriority(t *testing.T) {
+	w := openTestWAL(t)
+
+	for _, evt := range []string{"mouse_movement", "file_access", "mouse_movement", "keystroke"} {
+		if err := w.Append(evt, []byte(evt)); err != nil {
+			t.Fatalf("Append: %v", err)
+		}
+	}
+
+	priority := map[string]int{"mouse_movement": -10, "file_access": 10}
+	// keystroke is absent, so it defaults to priority 0 -- above
+	// mouse_movement's -10, so mouse_movement entries evict first.
+	n, err := w.EvictLowestPriority(priority, 1)
+	if err != nil {
+		t.Fatalf("EvictLowestPriority: %v", err)
+	}
+	if n != 1 {
+		t.Fatalf("EvictLowestPriority removed %d, want 1", n)
+	}
+
+	entries, err := w.Peek(10)
+	if err != nil {
+		t.Fatalf("Peek: %v", err)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("remaining entries = %+v, want 3", entries)
+	}
+	// Exactly one mouse_movement should remain (two were queued, one evicted).
+	remainingMouse := 0
+	for _, e := range entries {
+		if e.EventType == "mouse_movement" {
+			remainingMouse++
+		}
+	}
+	if remainingMouse != 1 {
+		t.Fatalf("remaining mouse_movement entries = %d, want 1", remainingMouse)
+	}
+}
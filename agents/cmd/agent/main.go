@@ -0,0 +1,97 @@
+// Command agent runs the ZehraGuard behavioral agent as a standalone
+// process on an endpoint.
+package main
+
+import (
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/yashab-cyber/zehraguard/agents/agent"
+	"github.com/yashab-cyber/zehraguard/agents/buffer"
+)
+
+func envOr(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
+
+// envCollectors splits a comma-separated ZEHRAGUARD_COLLECTORS value (e.g.
+// "evdev,ebpf") into collector names, falling back to fallback when unset.
+func envCollectors(key string, fallback []string) []string {
+	v := os.Getenv(key)
+	if v == "" {
+		return fallback
+	}
+	var names []string
+	for _, name := range strings.Split(v, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return fallback
+	}
+	return names
+}
+
+func loadConfig() *agent.AgentConfig {
+	userID := os.Getenv("ZEHRAGUARD_USER_ID")
+	if userID == "" {
+		userID = "default_user"
+	}
+
+	return &agent.AgentConfig{
+		Redis: agent.RedisConfig{
+			Addr:               "localhost:6379",
+			CredentialProvider: agent.FileCredential(envOr("ZEHRAGUARD_REDIS_PASSWORD_FILE", "/etc/zehraguard/redis_password")),
+			ReauthInterval:     time.Hour,
+		},
+		ServerURL:         "ws://localhost:8000",
+		CollectKeystrokes: true,
+		CollectMouse:      true,
+		CollectFiles:      true,
+		CollectNetwork:    true,
+		SampleRate:        10,
+		Collectors:        envCollectors("ZEHRAGUARD_COLLECTORS", []string{"synthetic"}),
+		DebugAddr:         "127.0.0.1:6061",
+		ManagerURL:        os.Getenv("ZEHRAGUARD_MANAGER_URL"),
+		ManagerAgentID:    os.Getenv("ZEHRAGUARD_MANAGER_AGENT_ID"),
+		ManagerToken:      os.Getenv("ZEHRAGUARD_MANAGER_TOKEN"),
+		Buffer: buffer.Config{
+			Path:          "/var/lib/zehraguard/agent.wal",
+			MaxDiskBytes:  512 * 1024 * 1024,
+			MaxAgeHours:   72,
+			DropPolicy:    buffer.DropOldest,
+			HighWaterMark: 10000,
+		},
+	}
+}
+
+func main() {
+	config := loadConfig()
+	userID := os.Getenv("ZEHRAGUARD_USER_ID")
+	if userID == "" {
+		userID = "default_user"
+	}
+
+	a, err := agent.NewBehavioralAgent(userID, config)
+	if err != nil {
+		log.Fatalf("Failed to create agent: %v", err)
+	}
+
+	go func() {
+		if err := a.Start(); err != nil {
+			log.Fatalf("Agent failed: %v", err)
+		}
+	}()
+
+	agent.WaitForShutdown(func() { a.Stop() }, func() {
+		if err := a.Reload(loadConfig()); err != nil {
+			log.Printf("Reload failed: %v", err)
+		}
+	})
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type contextKey string
+
+const claimsContextKey contextKey = "claims"
+
+// Claims is the JWT payload the manager expects from an authenticated
+// operator or CI system pushing fleet changes.
+type Claims struct {
+	jwt.RegisteredClaims
+	Role string `json:"role"` // "admin", "operator", "readonly"
+}
+
+// authMiddleware validates the bearer token on every request and stashes
+// its Claims in the request context for handlers/audit logging to read.
+func authMiddleware(secret []byte, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header := r.Header.Get("Authorization")
+		token, ok := strings.CutPrefix(header, "Bearer ")
+		if !ok || token == "" {
+			http.Error(w, "missing bearer token", http.StatusUnauthorized)
+			return
+		}
+
+		claims := &Claims{}
+		parsed, err := jwt.ParseWithClaims(token, claims, func(t *jwt.Token) (interface{}, error) {
+			return secret, nil
+		})
+		if err != nil || !parsed.Valid {
+			http.Error(w, "invalid token", http.StatusUnauthorized)
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), claimsContextKey, claims)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// requireRole wraps a handler so it only proceeds if the request's Claims
+// carry one of the allowed roles.
+func requireRole(next http.HandlerFunc, allowed ...string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := r.Context().Value(claimsContextKey).(*Claims)
+		if !ok {
+			http.Error(w, "unauthenticated", http.StatusUnauthorized)
+			return
+		}
+		for _, role := range allowed {
+			if claims.Role == role {
+				next(w, r)
+				return
+			}
+		}
+		http.Error(w, "forbidden", http.StatusForbidden)
+	}
+}
+
+func actorFromRequest(r *http.Request) string {
+	claims, ok := r.Context().Value(claimsContextKey).(*Claims)
+	if !ok {
+		return "unknown"
+	}
+	return claims.Subject
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var testSecret = []byte("test-secret")
+
+func signTestToken(t *testing.T, subject, role string) string {
+	t.Helper()
+	claims := &Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   subject,
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+		Role: role,
+	}
+	tok, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(testSecret)
+	if err != nil {
+		t.Fatalf("sign token: %v", err)
+	}
+	return tok
+}
+
+func TestAuthMiddlewareRejectsMissingToken(t *testing.T) {
+	handler := authMiddleware(testSecret, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Fatal("handler should not run without a token")
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/agents", nil)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusUnauthorized)
+	}
+}
+
+func TestAuthMiddlewareRejectsBadSignature(t *testing.T) {
+	handler := authMiddleware(testSecret, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Fatal("handler should not run with an invalid token")
+	}))
+
+	tok, err := jwt.NewWithClaims(jwt.SigningMethodHS256, &Claims{Role: "admin"}).SignedString([]byte("wrong-secret"))
+	if err != nil {
+		t.Fatalf("sign token: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/agents", nil)
+	req.Header.Set("Authorization", "Bearer "+tok)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusUnauthorized)
+	}
+}
+
+func TestAuthMiddlewareAcceptsValidToken(t *testing.T) {
+	var gotActor string
+	inner := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotActor = actorFromRequest(r)
+		w.WriteHeader(http.StatusOK)
+	})
+	handler := authMiddleware(testSecret, inner)
+
+	req := httptest.NewRequest(http.MethodGet, "/agents", nil)
+	req.Header.Set("Authorization", "Bearer "+signTestToken(t, "alice", "operator"))
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+	if gotActor != "alice" {
+		t.Fatalf("actorFromRequest = %q, want %q", gotActor, "alice")
+	}
+}
+
+func TestRequireRole(t *testing.T) {
+	inner := func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) }
+
+	tests := []struct {
+		name    string
+		role    string
+		allowed []string
+		want    int
+	}{
+		{"allowed role", "admin", []string{"admin", "operator"}, http.StatusOK},
+		{"disallowed role", "readonly", []string{"admin", "operator"}, http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := authMiddleware(testSecret, requireRole(inner, tt.allowed...))
+
+			req := httptest.NewRequest(http.MethodGet, "/agents", nil)
+			req.Header.Set("Authorization", "Bearer "+signTestToken(t, "bob", tt.role))
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.want {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.want)
+			}
+		})
+	}
+}
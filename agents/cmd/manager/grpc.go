@@ -0,0 +1,251 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// The manager's gRPC service mirrors its REST fleet-management API for
+// operators and internal services that prefer it (e.g. a CI pipeline
+// pushing bulk config changes). Its wire contract is documented in
+// proto/agentmanager.proto, but the messages and service below are
+// hand-written rather than protoc-generated: this repo has no protoc /
+// protoc-gen-go-grpc build step yet, and shipping code that imports a
+// generated package no one can regenerate is worse than not generating it
+// at all. Wire encoding uses a JSON codec (jsonCodec, below) instead of
+// protobuf binary framing, so these types need only implement plain Go
+// struct (un)marshaling — no protoreflect/proto.Message machinery.
+//
+// Agent, CreateAgentRequest, etc. below correspond 1:1 to the messages in
+// agentmanager.proto; keep them in sync if the .proto changes.
+
+// Agent is the gRPC-facing view of a fleet member.
+type Agent struct {
+	ID                string    `json:"id"`
+	UserID            string    `json:"user_id"`
+	Hostname          string    `json:"hostname"`
+	RegisteredAt      time.Time `json:"registered_at"`
+	LastSeenAt        time.Time `json:"last_seen_at"`
+	DesiredConfigJSON string    `json:"desired_config_json"`
+	ConfigVersion     int32     `json:"config_version"`
+}
+
+type CreateAgentRequest struct {
+	UserID   string `json:"user_id"`
+	Hostname string `json:"hostname"`
+}
+
+type GetAgentRequest struct {
+	ID string `json:"id"`
+}
+
+type ListAgentsRequest struct{}
+
+type ListAgentsResponse struct {
+	Agents []*Agent `json:"agents"`
+}
+
+type DeleteAgentRequest struct {
+	ID string `json:"id"`
+}
+
+type DeleteAgentResponse struct{}
+
+type PushConfigRequest struct {
+	ID         string `json:"id"`
+	ConfigJSON string `json:"config_json"`
+}
+
+type PushConfigResponse struct {
+	ConfigVersion int32 `json:"config_version"`
+}
+
+// grpcServer mirrors Server's REST endpoints over gRPC, for operators and
+// internal services (e.g. a CI pipeline pushing bulk config changes) that
+// prefer it. It shares the same Store, so REST and gRPC clients see a
+// consistent view of the fleet.
+type grpcServer struct {
+	store *Store
+}
+
+func newGRPCServer(store *Store) *grpcServer {
+	return &grpcServer{store: store}
+}
+
+func (g *grpcServer) CreateAgent(ctx context.Context, req *CreateAgentRequest) (*Agent, error) {
+	id, err := newAgentID()
+	if err != nil {
+		return nil, status.Error(codes.Internal, "failed to generate agent id")
+	}
+
+	now := time.Now()
+	rec := AgentRecord{
+		ID:            id,
+		UserID:        req.UserID,
+		Hostname:      req.Hostname,
+		RegisteredAt:  now,
+		LastSeenAt:    now,
+		DesiredConfig: json.RawMessage(`{}`),
+	}
+	if err := g.store.CreateAgent(ctx, rec); err != nil {
+		return nil, status.Error(codes.Internal, "failed to register agent")
+	}
+	g.store.AppendAudit(ctx, actorFromGRPC(ctx), "create_agent", id, req.Hostname)
+
+	return toGRPCAgent(rec), nil
+}
+
+func (g *grpcServer) GetAgent(ctx context.Context, req *GetAgentRequest) (*Agent, error) {
+	rec, err := g.store.GetAgent(ctx, req.ID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, status.Error(codes.NotFound, "agent not found")
+	} else if err != nil {
+		return nil, status.Error(codes.Internal, "failed to fetch agent")
+	}
+	return toGRPCAgent(rec), nil
+}
+
+func (g *grpcServer) ListAgents(ctx context.Context, _ *ListAgentsRequest) (*ListAgentsResponse, error) {
+	recs, err := g.store.ListAgents(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "failed to list agents")
+	}
+	resp := &ListAgentsResponse{}
+	for _, rec := range recs {
+		resp.Agents = append(resp.Agents, toGRPCAgent(rec))
+	}
+	return resp, nil
+}
+
+func (g *grpcServer) DeleteAgent(ctx context.Context, req *DeleteAgentRequest) (*DeleteAgentResponse, error) {
+	if err := g.store.DeleteAgent(ctx, req.ID); errors.Is(err, sql.ErrNoRows) {
+		return nil, status.Error(codes.NotFound, "agent not found")
+	} else if err != nil {
+		return nil, status.Error(codes.Internal, "failed to delete agent")
+	}
+	g.store.AppendAudit(ctx, actorFromGRPC(ctx), "delete_agent", req.ID, "")
+	return &DeleteAgentResponse{}, nil
+}
+
+func (g *grpcServer) PushConfig(ctx context.Context, req *PushConfigRequest) (*PushConfigResponse, error) {
+	version, err := g.store.UpdateDesiredConfig(ctx, req.ID, json.RawMessage(req.ConfigJSON))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, status.Error(codes.NotFound, "agent not found")
+	} else if err != nil {
+		return nil, status.Error(codes.Internal, "failed to update config")
+	}
+	g.store.AppendAudit(ctx, actorFromGRPC(ctx), "update_config", req.ID, req.ConfigJSON)
+	return &PushConfigResponse{ConfigVersion: int32(version)}, nil
+}
+
+func toGRPCAgent(rec AgentRecord) *Agent {
+	return &Agent{
+		ID:                rec.ID,
+		UserID:            rec.UserID,
+		Hostname:          rec.Hostname,
+		RegisteredAt:      rec.RegisteredAt,
+		LastSeenAt:        rec.LastSeenAt,
+		DesiredConfigJSON: string(rec.DesiredConfig),
+		ConfigVersion:     int32(rec.ConfigVersion),
+	}
+}
+
+// agentManagerServiceDesc and the handlers below stand in for what
+// protoc-gen-go-grpc would otherwise generate from agentmanager.proto's
+// "service AgentManager" block: a grpc.ServiceDesc naming each method and a
+// handler that decodes the request, invokes the matching grpcServer method,
+// and runs any configured interceptor chain.
+var agentManagerServiceDesc = grpc.ServiceDesc{
+	ServiceName: "zehraguard.agentmanager.v1.AgentManager",
+	HandlerType: (*grpcServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{MethodName: "CreateAgent", Handler: agentManagerCreateAgentHandler},
+		{MethodName: "GetAgent", Handler: agentManagerGetAgentHandler},
+		{MethodName: "ListAgents", Handler: agentManagerListAgentsHandler},
+		{MethodName: "DeleteAgent", Handler: agentManagerDeleteAgentHandler},
+		{MethodName: "PushConfig", Handler: agentManagerPushConfigHandler},
+	},
+	Metadata: "agentmanager.proto",
+}
+
+func agentManagerCreateAgentHandler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateAgentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(*grpcServer).CreateAgent(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/zehraguard.agentmanager.v1.AgentManager/CreateAgent"}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(*grpcServer).CreateAgent(ctx, req.(*CreateAgentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func agentManagerGetAgentHandler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAgentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(*grpcServer).GetAgent(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/zehraguard.agentmanager.v1.AgentManager/GetAgent"}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(*grpcServer).GetAgent(ctx, req.(*GetAgentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func agentManagerListAgentsHandler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListAgentsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(*grpcServer).ListAgents(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/zehraguard.agentmanager.v1.AgentManager/ListAgents"}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(*grpcServer).ListAgents(ctx, req.(*ListAgentsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func agentManagerDeleteAgentHandler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteAgentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(*grpcServer).DeleteAgent(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/zehraguard.agentmanager.v1.AgentManager/DeleteAgent"}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(*grpcServer).DeleteAgent(ctx, req.(*DeleteAgentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func agentManagerPushConfigHandler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PushConfigRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(*grpcServer).PushConfig(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/zehraguard.agentmanager.v1.AgentManager/PushConfig"}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(*grpcServer).PushConfig(ctx, req.(*PushConfigRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestGRPCRoleInterceptor(t *testing.T) {
+	inner := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "ok", nil
+	}
+
+	tests := []struct {
+		name       string
+		method     string
+		role       string
+		wantCode   codes.Code
+		wantResult bool
+	}{
+		{"admin may delete", "/zehraguard.agentmanager.v1.AgentManager/DeleteAgent", "admin", codes.OK, true},
+		{"operator may not delete", "/zehraguard.agentmanager.v1.AgentManager/DeleteAgent", "operator", codes.PermissionDenied, false},
+		{"readonly may not delete", "/zehraguard.agentmanager.v1.AgentManager/DeleteAgent", "readonly", codes.PermissionDenied, false},
+		{"readonly may list", "/zehraguard.agentmanager.v1.AgentManager/ListAgents", "readonly", codes.OK, true},
+		{"readonly may not push config", "/zehraguard.agentmanager.v1.AgentManager/PushConfig", "readonly", codes.PermissionDenied, false},
+		{"operator may push config", "/zehraguard.agentmanager.v1.AgentManager/PushConfig", "operator", codes.OK, true},
+	}
+
+	interceptor := grpcRoleInterceptor()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.WithValue(context.Background(), claimsContextKey, &Claims{Role: tt.role})
+			info := &grpc.UnaryServerInfo{FullMethod: tt.method}
+			resp, err := interceptor(ctx, nil, info, inner)
+
+			if tt.wantResult && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !tt.wantResult {
+				if err == nil {
+					t.Fatalf("expected error, got response %v", resp)
+				}
+				if status.Code(err) != tt.wantCode {
+					t.Fatalf("code = %v, want %v", status.Code(err), tt.wantCode)
+				}
+			}
+		})
+	}
+}
+
+func TestGRPCRoleInterceptorRejectsUnauthenticated(t *testing.T) {
+	inner := func(ctx context.Context, req interface{}) (interface{}, error) {
+		t.Fatal("handler should not run without claims")
+		return nil, nil
+	}
+
+	interceptor := grpcRoleInterceptor()
+	info := &grpc.UnaryServerInfo{FullMethod: "/zehraguard.agentmanager.v1.AgentManager/ListAgents"}
+	_, err := interceptor(context.Background(), nil, info, inner)
+	if status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("code = %v, want %v", status.Code(err), codes.Unauthenticated)
+	}
+}
@@ -0,0 +1,22 @@
+package main
+
+import "encoding/json"
+
+// jsonCodec marshals gRPC messages as JSON instead of protobuf binary
+// framing, so the manager's gRPC service (see grpc.go) can be served
+// without a protoc/protoc-gen-go-grpc codegen step. It's forced on the
+// server via grpc.ForceServerCodec, so no client content-type negotiation
+// is required.
+type jsonCodec struct{}
+
+func (jsonCodec) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (jsonCodec) Unmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
+
+func (jsonCodec) Name() string {
+	return "json"
+}
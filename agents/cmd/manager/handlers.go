@@ -0,0 +1,257 @@
+package main
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/yashab-cyber/zehraguard/agents/transport"
+)
+
+// Server holds the manager's dependencies and implements its HTTP handlers.
+type Server struct {
+	store  *Store
+	webrtc *webrtcCoordinator
+}
+
+func NewServer(store *Store, ice transport.ICEConfig) *Server {
+	return &Server{store: store, webrtc: newWebRTCCoordinator(ice)}
+}
+
+// Routes registers the manager's REST endpoints on r.
+func (s *Server) Routes(r *mux.Router, jwtSecret []byte) {
+	r.HandleFunc("/health", s.handleHealth).Methods(http.MethodGet)
+
+	protected := r.NewRoute().Subrouter()
+	protected.Use(func(next http.Handler) http.Handler {
+		return authMiddleware(jwtSecret, next)
+	})
+
+	protected.HandleFunc("/agents", requireRole(s.handleCreateAgent, "admin", "operator")).Methods(http.MethodPost)
+	protected.HandleFunc("/agents", requireRole(s.handleListAgents, "admin", "operator", "readonly")).Methods(http.MethodGet)
+	protected.HandleFunc("/agents/{id}", requireRole(s.handleGetAgent, "admin", "operator", "readonly")).Methods(http.MethodGet)
+	protected.HandleFunc("/agents/{id}", requireRole(s.handleDeleteAgent, "admin")).Methods(http.MethodDelete)
+	protected.HandleFunc("/agents/{id}/config", requireRole(s.handlePatchConfig, "admin", "operator")).Methods(http.MethodPatch)
+	protected.HandleFunc("/agents/{id}/config", requireRole(s.handleGetConfig, "admin", "operator", "readonly")).Methods(http.MethodGet)
+	protected.HandleFunc("/agents/{id}/events", requireRole(s.handleStreamEvents, "admin", "operator", "readonly")).Methods(http.MethodGet)
+	protected.HandleFunc("/debug/agents", requireRole(s.handleDebugAgents, "admin", "operator", "readonly")).Methods(http.MethodGet)
+	protected.HandleFunc("/agents/webrtc/ice-config", requireRole(s.handleICEConfig, "admin", "operator", "readonly")).Methods(http.MethodGet)
+	protected.HandleFunc("/agents/webrtc/offer", requireRole(s.handleWebRTCOffer, "admin", "operator", "readonly")).Methods(http.MethodPost)
+}
+
+func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("OK"))
+}
+
+type createAgentRequest struct {
+	UserID   string `json:"user_id"`
+	Hostname string `json:"hostname"`
+}
+
+func (s *Server) handleCreateAgent(w http.ResponseWriter, r *http.Request) {
+	var req createAgentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	id, err := newAgentID()
+	if err != nil {
+		http.Error(w, "failed to generate agent id", http.StatusInternalServerError)
+		return
+	}
+
+	now := time.Now()
+	rec := AgentRecord{
+		ID:            id,
+		UserID:        req.UserID,
+		Hostname:      req.Hostname,
+		RegisteredAt:  now,
+		LastSeenAt:    now,
+		DesiredConfig: json.RawMessage(`{}`),
+	}
+	if err := s.store.CreateAgent(r.Context(), rec); err != nil {
+		http.Error(w, "failed to register agent", http.StatusInternalServerError)
+		return
+	}
+	s.store.AppendAudit(r.Context(), actorFromRequest(r), "create_agent", id, req.Hostname)
+
+	respondJSON(w, http.StatusCreated, rec)
+}
+
+func (s *Server) handleListAgents(w http.ResponseWriter, r *http.Request) {
+	agents, err := s.store.ListAgents(r.Context())
+	if err != nil {
+		http.Error(w, "failed to list agents", http.StatusInternalServerError)
+		return
+	}
+	respondJSON(w, http.StatusOK, agents)
+}
+
+func (s *Server) handleGetAgent(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+	rec, err := s.store.GetAgent(r.Context(), id)
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, "agent not found", http.StatusNotFound)
+		return
+	} else if err != nil {
+		http.Error(w, "failed to fetch agent", http.StatusInternalServerError)
+		return
+	}
+	respondJSON(w, http.StatusOK, rec)
+}
+
+func (s *Server) handleDeleteAgent(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+	if err := s.store.DeleteAgent(r.Context(), id); errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, "agent not found", http.StatusNotFound)
+		return
+	} else if err != nil {
+		http.Error(w, "failed to delete agent", http.StatusInternalServerError)
+		return
+	}
+	s.store.AppendAudit(r.Context(), actorFromRequest(r), "delete_agent", id, "")
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) handleGetConfig(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+	rec, err := s.store.GetAgent(r.Context(), id)
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, "agent not found", http.StatusNotFound)
+		return
+	} else if err != nil {
+		http.Error(w, "failed to fetch config", http.StatusInternalServerError)
+		return
+	}
+	if err := s.store.TouchLastSeen(r.Context(), id, time.Now()); err != nil {
+		http.Error(w, "failed to record last-seen", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("X-Config-Version", strconv.Itoa(rec.ConfigVersion))
+	respondJSON(w, http.StatusOK, rec.DesiredConfig)
+}
+
+// handlePatchConfig stores a new desired AgentConfig for the agent. The
+// agent picks it up by polling GET on this same path (comparing
+// X-Config-Version) and applies it the same way a SIGHUP reload does;
+// nothing pushes it over the agent's websocket connection.
+func (s *Server) handlePatchConfig(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+
+	var raw json.RawMessage
+	if err := json.NewDecoder(r.Body).Decode(&raw); err != nil {
+		http.Error(w, "invalid config body", http.StatusBadRequest)
+		return
+	}
+
+	version, err := s.store.UpdateDesiredConfig(r.Context(), id, raw)
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, "agent not found", http.StatusNotFound)
+		return
+	} else if err != nil {
+		http.Error(w, "failed to update config", http.StatusInternalServerError)
+		return
+	}
+	s.store.AppendAudit(r.Context(), actorFromRequest(r), "update_config", id, string(raw))
+
+	respondJSON(w, http.StatusOK, map[string]int{"config_version": version})
+}
+
+// handleStreamEvents returns agentID's audit-log entries (config pushes,
+// registration, deletion) with id greater than the "since" cursor, so a
+// caller can page through fleet-management history for that agent. It also
+// touches the agent's last-seen timestamp, the same as a config poll does.
+func (s *Server) handleStreamEvents(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+	if err := s.store.TouchLastSeen(r.Context(), id, time.Now()); err != nil {
+		http.Error(w, "failed to record last-seen", http.StatusInternalServerError)
+		return
+	}
+
+	var since int64
+	if raw := r.URL.Query().Get("since"); raw != "" {
+		v, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			http.Error(w, "invalid since cursor", http.StatusBadRequest)
+			return
+		}
+		since = v
+	}
+
+	events, err := s.store.AuditSince(r.Context(), id, since)
+	if err != nil {
+		http.Error(w, "failed to fetch events", http.StatusInternalServerError)
+		return
+	}
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"since":  since,
+		"events": events,
+	})
+}
+
+// staleAfter is how long an agent can go without a request touching its
+// last-seen timestamp (a config poll or event stream call) before
+// handleDebugAgents flags it as stale.
+const staleAfter = 2 * time.Minute
+
+// fleetDebugAgent is one row of the fleet-wide health view served at
+// GET /debug/agents. Unlike a single agent's own /debug/agent endpoint
+// (which reports its supervisor task/collector state), this is a
+// manager-side view built entirely from what the manager already knows
+// about each agent, so it works without reaching into every agent process.
+type fleetDebugAgent struct {
+	ID            string    `json:"id"`
+	UserID        string    `json:"user_id"`
+	Hostname      string    `json:"hostname"`
+	LastSeenAt    time.Time `json:"last_seen_at"`
+	ConfigVersion int       `json:"config_version"`
+	Stale         bool      `json:"stale"`
+}
+
+// handleDebugAgents gives operators a fleet-wide health view: every
+// registered agent, when it was last seen (via a config poll or event
+// stream call), and whether it's gone quiet longer than staleAfter.
+func (s *Server) handleDebugAgents(w http.ResponseWriter, r *http.Request) {
+	recs, err := s.store.ListAgents(r.Context())
+	if err != nil {
+		http.Error(w, "failed to list agents", http.StatusInternalServerError)
+		return
+	}
+
+	now := time.Now()
+	out := make([]fleetDebugAgent, 0, len(recs))
+	for _, rec := range recs {
+		out = append(out, fleetDebugAgent{
+			ID:            rec.ID,
+			UserID:        rec.UserID,
+			Hostname:      rec.Hostname,
+			LastSeenAt:    rec.LastSeenAt,
+			ConfigVersion: rec.ConfigVersion,
+			Stale:         now.Sub(rec.LastSeenAt) > staleAfter,
+		})
+	}
+	respondJSON(w, http.StatusOK, out)
+}
+
+func respondJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func newAgentID() (string, error) {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return "agent_" + hex.EncodeToString(buf), nil
+}
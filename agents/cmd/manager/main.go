@@ -1,24 +1,59 @@
+// Command manager runs the ZehraGuard agent-manager: the fleet control
+// plane that registers agents, tracks their health, and pushes config
+// changes out over REST and gRPC.
 package main
 
 import (
 	"log"
+	"net"
 	"net/http"
+	"os"
+
 	"github.com/gorilla/mux"
+	"google.golang.org/grpc"
 )
 
 func main() {
+	dbPath := envOr("ZEHRAGUARD_MANAGER_DB", "manager.db")
+	store, err := OpenStore("sqlite3", dbPath)
+	if err != nil {
+		log.Fatalf("Failed to open store: %v", err)
+	}
+	defer store.Close()
+
+	jwtSecret := []byte(envOr("ZEHRAGUARD_MANAGER_JWT_SECRET", ""))
+	if len(jwtSecret) == 0 {
+		log.Fatal("ZEHRAGUARD_MANAGER_JWT_SECRET must be set")
+	}
+
+	server := NewServer(store, parseICEConfigEnv())
 	r := mux.NewRouter()
-	
-	r.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(http.StatusOK)
-		w.Write([]byte("OK"))
-	}).Methods("GET")
-	
-	r.HandleFunc("/agents", func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Content-Type", "application/json")
-		w.Write([]byte(`{"agents": []}`))
-	}).Methods("GET")
-
-	log.Println("Agent Manager starting on :8001")
-	log.Fatal(http.ListenAndServe(":8001", r))
+	server.Routes(r, jwtSecret)
+
+	go func() {
+		log.Println("Agent Manager REST API starting on :8001")
+		log.Fatal(http.ListenAndServe(":8001", r))
+	}()
+
+	grpcAddr := envOr("ZEHRAGUARD_MANAGER_GRPC_ADDR", ":8002")
+	lis, err := net.Listen("tcp", grpcAddr)
+	if err != nil {
+		log.Fatalf("Failed to listen for gRPC: %v", err)
+	}
+
+	grpcSrv := grpc.NewServer(
+		grpc.ForceServerCodec(jsonCodec{}),
+		grpc.ChainUnaryInterceptor(jwtUnaryInterceptor(jwtSecret), grpcRoleInterceptor()),
+	)
+	grpcSrv.RegisterService(&agentManagerServiceDesc, newGRPCServer(store))
+
+	log.Printf("Agent Manager gRPC API starting on %s", grpcAddr)
+	log.Fatal(grpcSrv.Serve(lis))
+}
+
+func envOr(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
 }
@@ -0,0 +1,216 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// AgentRecord is a registered fleet member as persisted by the manager.
+type AgentRecord struct {
+	ID            string          `json:"id"`
+	UserID        string          `json:"user_id"`
+	Hostname      string          `json:"hostname"`
+	RegisteredAt  time.Time       `json:"registered_at"`
+	LastSeenAt    time.Time       `json:"last_seen_at"`
+	DesiredConfig json.RawMessage `json:"desired_config"`
+	ConfigVersion int             `json:"config_version"`
+}
+
+// Store persists agent records and audit entries. It is backed by
+// database/sql, so the same queries work unmodified against SQLite (the
+// default, zero-dependency choice for a single manager instance) or
+// Postgres for a multi-instance deployment; only the DSN/driver differ.
+type Store struct {
+	db *sql.DB
+}
+
+// OpenStore opens (and migrates) the store at the given database/sql
+// driver and DSN, e.g. ("sqlite3", "manager.db").
+func OpenStore(driver, dsn string) (*Store, error) {
+	db, err := sql.Open(driver, dsn)
+	if err != nil {
+		return nil, fmt.Errorf("open store: %w", err)
+	}
+	s := &Store{db: db}
+	if err := s.migrate(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("migrate store: %w", err)
+	}
+	return s, nil
+}
+
+func (s *Store) migrate() error {
+	_, err := s.db.Exec(`
+		CREATE TABLE IF NOT EXISTS agents (
+			id             TEXT PRIMARY KEY,
+			user_id        TEXT NOT NULL,
+			hostname       TEXT NOT NULL,
+			registered_at  DATETIME NOT NULL,
+			last_seen_at   DATETIME NOT NULL,
+			desired_config TEXT NOT NULL DEFAULT '{}',
+			config_version INTEGER NOT NULL DEFAULT 0
+		);
+		CREATE TABLE IF NOT EXISTS audit_log (
+			id         INTEGER PRIMARY KEY AUTOINCREMENT,
+			at         DATETIME NOT NULL,
+			actor      TEXT NOT NULL,
+			action     TEXT NOT NULL,
+			agent_id   TEXT NOT NULL,
+			detail     TEXT NOT NULL
+		);
+	`)
+	return err
+}
+
+// Close releases the underlying database handle.
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// CreateAgent registers a new agent.
+func (s *Store) CreateAgent(ctx context.Context, rec AgentRecord) error {
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO agents (id, user_id, hostname, registered_at, last_seen_at, desired_config, config_version)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		rec.ID, rec.UserID, rec.Hostname, rec.RegisteredAt, rec.LastSeenAt, string(rec.DesiredConfig), rec.ConfigVersion)
+	if err != nil {
+		return fmt.Errorf("create agent: %w", err)
+	}
+	return nil
+}
+
+// GetAgent fetches a single agent by ID.
+func (s *Store) GetAgent(ctx context.Context, id string) (AgentRecord, error) {
+	row := s.db.QueryRowContext(ctx, `
+		SELECT id, user_id, hostname, registered_at, last_seen_at, desired_config, config_version
+		FROM agents WHERE id = ?`, id)
+	return scanAgent(row)
+}
+
+// ListAgents returns every registered agent.
+func (s *Store) ListAgents(ctx context.Context) ([]AgentRecord, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id, user_id, hostname, registered_at, last_seen_at, desired_config, config_version
+		FROM agents ORDER BY registered_at`)
+	if err != nil {
+		return nil, fmt.Errorf("list agents: %w", err)
+	}
+	defer rows.Close()
+
+	var out []AgentRecord
+	for rows.Next() {
+		rec, err := scanAgent(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, rec)
+	}
+	return out, rows.Err()
+}
+
+// DeleteAgent removes an agent's registration.
+func (s *Store) DeleteAgent(ctx context.Context, id string) error {
+	res, err := s.db.ExecContext(ctx, `DELETE FROM agents WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("delete agent: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// UpdateDesiredConfig stores a new desired config for an agent and bumps
+// its version, so agents polling can detect the change.
+func (s *Store) UpdateDesiredConfig(ctx context.Context, id string, config json.RawMessage) (int, error) {
+	res, err := s.db.ExecContext(ctx, `
+		UPDATE agents SET desired_config = ?, config_version = config_version + 1
+		WHERE id = ?`, string(config), id)
+	if err != nil {
+		return 0, fmt.Errorf("update desired config: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	if n == 0 {
+		return 0, sql.ErrNoRows
+	}
+
+	rec, err := s.GetAgent(ctx, id)
+	if err != nil {
+		return 0, err
+	}
+	return rec.ConfigVersion, nil
+}
+
+// TouchLastSeen updates an agent's last-seen timestamp, e.g. on every
+// config poll or event stream connection.
+func (s *Store) TouchLastSeen(ctx context.Context, id string, at time.Time) error {
+	_, err := s.db.ExecContext(ctx, `UPDATE agents SET last_seen_at = ? WHERE id = ?`, at, id)
+	return err
+}
+
+// AppendAudit records a fleet-management action for later review.
+func (s *Store) AppendAudit(ctx context.Context, actor, action, agentID, detail string) error {
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO audit_log (at, actor, action, agent_id, detail) VALUES (?, ?, ?, ?, ?)`,
+		time.Now(), actor, action, agentID, detail)
+	return err
+}
+
+// AuditEntry is one recorded fleet-management action.
+type AuditEntry struct {
+	ID      int64     `json:"id"`
+	At      time.Time `json:"at"`
+	Actor   string    `json:"actor"`
+	Action  string    `json:"action"`
+	AgentID string    `json:"agent_id"`
+	Detail  string    `json:"detail"`
+}
+
+// AuditSince returns agentID's audit entries with id > sinceID, in id
+// order, so a caller can page through them by passing back the last ID it
+// saw. This backs the manager's GET /agents/{id}/events?since=... stream.
+func (s *Store) AuditSince(ctx context.Context, agentID string, sinceID int64) ([]AuditEntry, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id, at, actor, action, agent_id, detail
+		FROM audit_log WHERE agent_id = ? AND id > ? ORDER BY id`, agentID, sinceID)
+	if err != nil {
+		return nil, fmt.Errorf("audit since: %w", err)
+	}
+	defer rows.Close()
+
+	var out []AuditEntry
+	for rows.Next() {
+		var e AuditEntry
+		if err := rows.Scan(&e.ID, &e.At, &e.Actor, &e.Action, &e.AgentID, &e.Detail); err != nil {
+			return nil, err
+		}
+		out = append(out, e)
+	}
+	return out, rows.Err()
+}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanAgent(row scanner) (AgentRecord, error) {
+	var rec AgentRecord
+	var config string
+	if err := row.Scan(&rec.ID, &rec.UserID, &rec.Hostname, &rec.RegisteredAt, &rec.LastSeenAt, &config, &rec.ConfigVersion); err != nil {
+		return AgentRecord{}, err
+	}
+	rec.DesiredConfig = json.RawMessage(config)
+	return rec, nil
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/pion/webrtc/v3"
+
+	"github.com/yashab-cyber/zehraguard/agents/transport"
+)
+
+// webrtcCoordinator is the manager's signaling half of the WebRTC/QUIC
+// low-latency transport: it hands out the ICE/DERP relay configuration
+// agents should use and answers their SDP offers. It doesn't consume the
+// resulting data channel itself -- telemetry ingestion happens on the
+// separate ZehraGuard server (see agents/transport's package doc) -- so
+// once a channel opens the coordinator just keeps the peer connection
+// alive until it closes.
+type webrtcCoordinator struct {
+	ice transport.ICEConfig
+}
+
+func newWebRTCCoordinator(ice transport.ICEConfig) *webrtcCoordinator {
+	return &webrtcCoordinator{ice: ice}
+}
+
+// handleICEConfig serves the STUN/TURN servers agents should use for this
+// session, so operators can rotate TURN credentials by restarting the
+// manager instead of redeploying every agent.
+func (s *Server) handleICEConfig(w http.ResponseWriter, r *http.Request) {
+	respondJSON(w, http.StatusOK, s.webrtc.ice)
+}
+
+// handleWebRTCOffer answers an agent's SDP offer, completing the signaling
+// half of the WebRTC handshake. It takes the role of the answering peer:
+// the agent always creates the data channel, so this side only has to
+// react to webrtc.PeerConnection's OnDataChannel callback.
+func (s *Server) handleWebRTCOffer(w http.ResponseWriter, r *http.Request) {
+	var offer webrtc.SessionDescription
+	if err := json.NewDecoder(r.Body).Decode(&offer); err != nil {
+		http.Error(w, "invalid offer", http.StatusBadRequest)
+		return
+	}
+
+	servers := []webrtc.ICEServer{{URLs: s.webrtc.ice.STUNServers}}
+	servers = append(servers, s.webrtc.ice.TURNServers...)
+
+	pc, err := webrtc.NewPeerConnection(webrtc.Configuration{ICEServers: servers})
+	if err != nil {
+		http.Error(w, "failed to create peer connection", http.StatusInternalServerError)
+		return
+	}
+
+	actor := actorFromRequest(r)
+	pc.OnDataChannel(func(dc *webrtc.DataChannel) {
+		log.Printf("webrtc: data channel %q open for %s", dc.Label(), actor)
+	})
+	pc.OnConnectionStateChange(func(state webrtc.PeerConnectionState) {
+		switch state {
+		case webrtc.PeerConnectionStateFailed, webrtc.PeerConnectionStateClosed, webrtc.PeerConnectionStateDisconnected:
+			pc.Close()
+		}
+	})
+
+	if err := pc.SetRemoteDescription(offer); err != nil {
+		pc.Close()
+		http.Error(w, "failed to set remote description", http.StatusBadRequest)
+		return
+	}
+
+	answer, err := pc.CreateAnswer(nil)
+	if err != nil {
+		pc.Close()
+		http.Error(w, "failed to create answer", http.StatusInternalServerError)
+		return
+	}
+
+	gatherComplete := webrtc.GatheringCompletePromise(pc)
+	if err := pc.SetLocalDescription(answer); err != nil {
+		pc.Close()
+		http.Error(w, "failed to set local description", http.StatusInternalServerError)
+		return
+	}
+
+	select {
+	case <-gatherComplete:
+	case <-r.Context().Done():
+		pc.Close()
+		http.Error(w, "client disconnected during negotiation", http.StatusRequestTimeout)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, pc.LocalDescription())
+}
+
+// parseICEConfigEnv builds the ICE configuration handed out by
+// handleICEConfig from environment variables, so it can be rotated without
+// a redeploy: ZEHRAGUARD_STUN_SERVERS is a comma-separated list of STUN
+// URLs; ZEHRAGUARD_TURN_URL/USERNAME/CREDENTIAL configure a single TURN
+// relay. With none set, agents get STUN-only, best-effort NAT traversal.
+func parseICEConfigEnv() transport.ICEConfig {
+	cfg := transport.ICEConfig{}
+
+	if raw := envOr("ZEHRAGUARD_STUN_SERVERS", "stun:stun.l.google.com:19302"); raw != "" {
+		for _, url := range strings.Split(raw, ",") {
+			if url = strings.TrimSpace(url); url != "" {
+				cfg.STUNServers = append(cfg.STUNServers, url)
+			}
+		}
+	}
+
+	if turnURL := envOr("ZEHRAGUARD_TURN_URL", ""); turnURL != "" {
+		cfg.TURNServers = append(cfg.TURNServers, webrtc.ICEServer{
+			URLs:       []string{turnURL},
+			Username:   envOr("ZEHRAGUARD_TURN_USERNAME", ""),
+			Credential: envOr("ZEHRAGUARD_TURN_CREDENTIAL", ""),
+		})
+	}
+
+	return cfg
+}
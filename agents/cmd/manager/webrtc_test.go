@@ -0,0 +1,90 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/pion/webrtc/v3"
+
+	"github.com/yashab-cyber/zehraguard/agents/transport"
+)
+
+func TestHandleICEConfig(t *testing.T) {
+	ice := transport.ICEConfig{STUNServers: []string{"stun:stun.example.com:3478"}}
+	s := &Server{webrtc: newWebRTCCoordinator(ice)}
+
+	req := httptest.NewRequest(http.MethodGet, "/agents/webrtc/ice-config", nil)
+	rec := httptest.NewRecorder()
+	s.handleICEConfig(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+	var got transport.ICEConfig
+	if err := json.NewDecoder(rec.Body).Decode(&got); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if len(got.STUNServers) != 1 || got.STUNServers[0] != "stun:stun.example.com:3478" {
+		t.Fatalf("STUNServers = %v, want the configured server", got.STUNServers)
+	}
+}
+
+// TestHandleWebRTCOfferAnswersRealOffer drives handleWebRTCOffer with an
+// actual pion client, the same way agents/transport.NewWebRTCTransport
+// does, to confirm the coordinator completes a real SDP negotiation rather
+// than stubbing it out.
+func TestHandleWebRTCOfferAnswersRealOffer(t *testing.T) {
+	s := &Server{webrtc: newWebRTCCoordinator(transport.ICEConfig{})}
+
+	clientPC, err := webrtc.NewPeerConnection(webrtc.Configuration{})
+	if err != nil {
+		t.Fatalf("new client peer connection: %v", err)
+	}
+	defer clientPC.Close()
+
+	if _, err := clientPC.CreateDataChannel("zehraguard-telemetry", nil); err != nil {
+		t.Fatalf("create data channel: %v", err)
+	}
+
+	offer, err := clientPC.CreateOffer(nil)
+	if err != nil {
+		t.Fatalf("create offer: %v", err)
+	}
+	gatherComplete := webrtc.GatheringCompletePromise(clientPC)
+	if err := clientPC.SetLocalDescription(offer); err != nil {
+		t.Fatalf("set local description: %v", err)
+	}
+	select {
+	case <-gatherComplete:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out gathering client ICE candidates")
+	}
+
+	body, err := json.Marshal(clientPC.LocalDescription())
+	if err != nil {
+		t.Fatalf("marshal offer: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/agents/webrtc/offer", bytes.NewReader(body))
+	rec := httptest.NewRecorder()
+	s.handleWebRTCOffer(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", rec.Code, rec.Body.String())
+	}
+
+	var answer webrtc.SessionDescription
+	if err := json.NewDecoder(rec.Body).Decode(&answer); err != nil {
+		t.Fatalf("decode answer: %v", err)
+	}
+	if answer.Type != webrtc.SDPTypeAnswer {
+		t.Fatalf("answer.Type = %v, want answer", answer.Type)
+	}
+	if err := clientPC.SetRemoteDescription(answer); err != nil {
+		t.Fatalf("client SetRemoteDescription(answer): %v", err)
+	}
+}
@@ -0,0 +1,109 @@
+// Package collectors defines the pluggable input-capture layer used by the
+// behavioral agent. Each Collector is responsible for turning real OS-level
+// input (keystrokes, mouse movement) into KeystrokeEvent/MouseEvent values
+// with timing taken from the timestamps the hook layer attaches to each
+// event. The evdev, Windows and macOS collectors report DwellTime as the
+// interval between successive key events seen by the hook and FlightTime
+// as 0.8 times that interval; they do not measure key-release timing. The
+// eBPF collector reports the dwell and flight times its kernel programs
+// record.
+package collectors
+
+import (
+	"context"
+	"fmt"
+)
+
+// KeystrokeEvent represents keystroke dynamics data captured by a Collector.
+type KeystrokeEvent struct {
+	KeyCode     int     `json:"key_code"`
+	DwellTime   float64 `json:"dwell_time"`
+	FlightTime  float64 `json:"flight_time"`
+	Pressure    float64 `json:"pressure"`
+	TypingSpeed float64 `json:"typing_speed"`
+	Sequence    string  `json:"sequence"`
+}
+
+// MouseEvent represents mouse movement data captured by a Collector.
+type MouseEvent struct {
+	X            int     `json:"x"`
+	Y            int     `json:"y"`
+	Velocity     float64 `json:"velocity"`
+	Acceleration float64 `json:"acceleration"`
+	ClickType    string  `json:"click_type"`
+	Pressure     float64 `json:"pressure"`
+	Trajectory   string  `json:"trajectory"`
+}
+
+// Collector captures raw OS-level input and delivers it on its channels
+// until Stop is called or the context passed to Start is cancelled.
+// Implementations must be safe to Stop more than once.
+type Collector interface {
+	// Name identifies the collector, e.g. "linux-evdev", "windows-hook",
+	// "macos-cgeventtap", or "synthetic".
+	Name() string
+
+	// Start begins capture. It must return promptly; capture itself runs
+	// in the background until ctx is cancelled or Stop is called.
+	Start(ctx context.Context) error
+
+	// Stop halts capture and releases any OS resources (file descriptors,
+	// hooks, eBPF links). It is safe to call Stop without a prior Start.
+	Stop() error
+
+	// Keystrokes delivers captured keystroke events. Implementations that
+	// do not support keystroke capture may return a nil channel.
+	Keystrokes() <-chan KeystrokeEvent
+
+	// Mice delivers captured mouse events. Implementations that do not
+	// support mouse capture may return a nil channel.
+	Mice() <-chan MouseEvent
+}
+
+// Config selects which collectors to run and how.
+type Config struct {
+	// Names lists the collectors to start, e.g. []string{"evdev", "ebpf"}.
+	// An empty list falls back to the platform default.
+	Names []string
+
+	// PreferEBPF requests the eBPF-backed Linux collector over plain evdev
+	// polling when both are available.
+	PreferEBPF bool
+
+	// EBPFObjectPath is the filesystem path to the compiled input-timing
+	// eBPF object, built from a C source that is not part of this
+	// repository. Only used by the
+	// "ebpf" collector, and only when the agent was built with the
+	// zehraguard_ebpf build tag. Defaults to
+	// "/etc/zehraguard/input_timing.bpf.o".
+	EBPFObjectPath string
+}
+
+// New builds the collectors requested by cfg for the current platform. It
+// returns an error if a requested collector is unknown or unsupported here.
+func New(cfg Config) ([]Collector, error) {
+	names := cfg.Names
+	if len(names) == 0 {
+		names = []string{defaultCollectorName(cfg)}
+	}
+
+	collectors := make([]Collector, 0, len(names))
+	for _, name := range names {
+		c, err := newCollector(name, cfg)
+		if err != nil {
+			return nil, fmt.Errorf("collectors: %w", err)
+		}
+		collectors = append(collectors, c)
+	}
+	return collectors, nil
+}
+
+func newCollector(name string, cfg Config) (Collector, error) {
+	if name == "synthetic" {
+		return NewSynthetic(10), nil
+	}
+	if c, ok, err := platformCollector(name, cfg); ok || err != nil {
+		return c, err
+	}
+	return nil, fmt.Errorf("unknown collector %q", name)
+}
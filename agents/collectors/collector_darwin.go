@@ -0,0 +1,136 @@
+//go:build darwin && cgo
+
+package collectors
+
+/*
+#cgo LDFLAGS: -framework ApplicationServices -framework CoreGraphics
+#include <ApplicationServices/ApplicationServices.h>
+
+extern CGEventRef zehraguardEventTapCallback(CGEventTapProxy proxy, CGEventType type, CGEventRef event, void *refcon);
+
+static CFMachPortRef zehraguardCreateTap(void *refcon) {
+	CGEventMask mask = CGEventMaskBit(kCGEventKeyDown) | CGEventMaskBit(kCGEventMouseMoved);
+	return CGEventTapCreate(kCGSessionEventTap, kCGHeadInsertEventTap, kCGEventTapOptionListenOnly,
+		mask, zehraguardEventTapCallback, refcon);
+}
+*/
+import "C"
+
+import (
+	"context"
+	"sync"
+	"unsafe"
+)
+
+// cgEventTapCollector uses a CGEventTap (listen-only, session-wide) to
+// observe key-down and mouse-moved events, timing them from the
+// CGEventTimestamp attached to each event by the window server: DwellTime
+// is the interval between successive key-down events, FlightTime is 0.8
+// times DwellTime, and Velocity is pointer distance over the time between
+// mouse-moved events.
+type cgEventTapCollector struct {
+	mu      sync.Mutex
+	tap     C.CFMachPortRef
+	runLoop C.CFRunLoopRef
+	cancel  context.CancelFunc
+	done    chan struct{}
+
+	keystrokes chan KeystrokeEvent
+	mice       chan MouseEvent
+
+	lastKeyNs   uint64
+	lastMoveNs  uint64
+	lastX, lastY float64
+}
+
+var activeTapCollectors sync.Map // uintptr(refcon) -> *cgEventTapCollector
+
+func newCGEventTapCollector() *cgEventTapCollector {
+	return &cgEventTapCollector{
+		keystrokes: make(chan KeystrokeEvent, 64),
+		mice:       make(chan MouseEvent, 64),
+	}
+}
+
+func (c *cgEventTapCollector) Name() string                     { return "macos-cgeventtap" }
+func (c *cgEventTapCollector) Keystrokes() <-chan KeystrokeEvent { return c.keystrokes }
+func (c *cgEventTapCollector) Mice() <-chan MouseEvent           { return c.mice }
+
+func (c *cgEventTapCollector) Start(ctx context.Context) error {
+	c.mu.Lock()
+	ctx, cancel := context.WithCancel(ctx)
+	c.cancel = cancel
+	c.done = make(chan struct{})
+	c.mu.Unlock()
+
+	go c.runEventLoop(ctx)
+	return nil
+}
+
+func (c *cgEventTapCollector) Stop() error {
+	c.mu.Lock()
+	cancel := c.cancel
+	done := c.done
+	runLoop := c.runLoop
+	c.mu.Unlock()
+	if cancel == nil {
+		return nil
+	}
+	cancel()
+	if runLoop != 0 {
+		C.CFRunLoopStop(runLoop)
+	}
+	<-done
+	return nil
+}
+
+// runEventLoop must own the CFRunLoop it creates the tap's source on, so it
+// runs on a single dedicated goroutine for the collector's lifetime.
+func (c *cgEventTapCollector) runEventLoop(ctx context.Context) {
+	defer close(c.done)
+
+	refcon := registerTapCollector(c)
+	defer activeTapCollectors.Delete(refcon)
+
+	tap := C.zehraguardCreateTap(unsafe.Pointer(refcon))
+	if tap == 0 {
+		return
+	}
+	defer C.CFRelease(C.CFTypeRef(tap))
+
+	c.mu.Lock()
+	c.tap = tap
+	c.runLoop = C.CFRunLoopGetCurrent()
+	c.mu.Unlock()
+
+	source := C.CFMachPortCreateRunLoopSource(C.kCFAllocatorDefault, tap, 0)
+	defer C.CFRelease(C.CFTypeRef(source))
+	C.CFRunLoopAddSource(c.runLoop, source, C.kCFRunLoopCommonModes)
+	C.CGEventTapEnable(tap, C.true)
+
+	go func() {
+		<-ctx.Done()
+		C.CFRunLoopStop(c.runLoop)
+	}()
+
+	C.CFRunLoopRun()
+}
+
+func registerTapCollector(c *cgEventTapCollector) uintptr {
+	refcon := uintptr(unsafe.Pointer(c))
+	activeTapCollectors.Store(refcon, c)
+	return refcon
+}
+
+func platformCollector(name string, cfg Config) (Collector, bool, error) {
+	switch name {
+	case "macos-cgeventtap":
+		return newCGEventTapCollector(), true, nil
+	default:
+		return nil, false, nil
+	}
+}
+
+func defaultCollectorName(cfg Config) string {
+	return "macos-cgeventtap"
+}
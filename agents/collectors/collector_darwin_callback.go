@@ -0,0 +1,54 @@
+//go:build darwin && cgo
+
+package collectors
+
+/*
+#include <ApplicationServices/ApplicationServices.h>
+*/
+import "C"
+
+import (
+	"math"
+	"unsafe"
+)
+
+//export zehraguardEventTapCallback
+func zehraguardEventTapCallback(proxy C.CGEventTapProxy, eventType C.CGEventType, event C.CGEventRef, refcon unsafe.Pointer) C.CGEventRef {
+	v, ok := activeTapCollectors.Load(uintptr(refcon))
+	if !ok {
+		return event
+	}
+	c := v.(*cgEventTapCollector)
+
+	ns := uint64(C.CGEventGetTimestamp(event))
+
+	switch eventType {
+	case C.kCGEventKeyDown:
+		keyCode := int(C.CGEventGetIntegerValueField(event, C.kCGKeyboardEventKeycode))
+		if c.lastKeyNs != 0 {
+			dwell := float64(ns-c.lastKeyNs) / 1e6
+			select {
+			case c.keystrokes <- KeystrokeEvent{KeyCode: keyCode, DwellTime: dwell, FlightTime: dwell * 0.8}:
+			default:
+			}
+		}
+		c.lastKeyNs = ns
+	case C.kCGEventMouseMoved:
+		loc := C.CGEventGetLocation(event)
+		x, y := float64(loc.x), float64(loc.y)
+		if c.lastMoveNs != 0 {
+			dt := float64(ns-c.lastMoveNs) / 1e9
+			if dt > 0 {
+				dx, dy := x-c.lastX, y-c.lastY
+				velocity := math.Sqrt(dx*dx+dy*dy) / dt
+				select {
+				case c.mice <- MouseEvent{X: int(x), Y: int(y), Velocity: velocity, ClickType: "move"}:
+				default:
+				}
+			}
+		}
+		c.lastMoveNs, c.lastX, c.lastY = ns, x, y
+	}
+
+	return event
+}
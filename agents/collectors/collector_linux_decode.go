@@ -0,0 +1,51 @@
+//go:build linux && zehraguard_ebpf
+
+package collectors
+
+import "encoding/binary"
+
+// keyRecord/mouseRecord mirror the packed structs the eBPF programs push
+// into their ring buffers (their C source is not part of this repository).
+type keyRecord struct {
+	KeyCode    uint32
+	DwellNs    uint64
+	FlightNs   uint64
+}
+
+type mouseRecord struct {
+	DX, DY   int32
+	Velocity uint64 // fixed-point, micro-units/sec
+}
+
+func decodeKeyRecord(raw []byte) (KeystrokeEvent, bool) {
+	if len(raw) < 20 {
+		return KeystrokeEvent{}, false
+	}
+	r := keyRecord{
+		KeyCode:  binary.LittleEndian.Uint32(raw[0:4]),
+		DwellNs:  binary.LittleEndian.Uint64(raw[4:12]),
+		FlightNs: binary.LittleEndian.Uint64(raw[12:20]),
+	}
+	return KeystrokeEvent{
+		KeyCode:    int(r.KeyCode),
+		DwellTime:  float64(r.DwellNs) / 1e6,
+		FlightTime: float64(r.FlightNs) / 1e6,
+	}, true
+}
+
+func decodeMouseRecord(raw []byte) (MouseEvent, bool) {
+	if len(raw) < 16 {
+		return MouseEvent{}, false
+	}
+	r := mouseRecord{
+		DX:       int32(binary.LittleEndian.Uint32(raw[0:4])),
+		DY:       int32(binary.LittleEndian.Uint32(raw[4:8])),
+		Velocity: binary.LittleEndian.Uint64(raw[8:16]),
+	}
+	return MouseEvent{
+		X:         int(r.DX),
+		Y:         int(r.DY),
+		Velocity:  float64(r.Velocity) / 1e6,
+		ClickType: "move",
+	}, true
+}
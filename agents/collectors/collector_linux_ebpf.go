@@ -0,0 +1,202 @@
+//go:build linux && zehraguard_ebpf
+
+package collectors
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/cilium/ebpf"
+	"github.com/cilium/ebpf/link"
+	"github.com/cilium/ebpf/ringbuf"
+)
+
+// defaultEBPFObjectPath is used when Config.EBPFObjectPath is empty.
+const defaultEBPFObjectPath = "/etc/zehraguard/input_timing.bpf.o"
+
+func init() {
+	newEBPFCollectorFunc = newEBPFCollector
+	ebpfBuilt = true
+}
+
+// ebpfCollector attaches kprobes (via cilium/ebpf) to the kernel input
+// subsystem so keystroke and pointer timing can be measured without polling
+// evdev in userspace. It falls back to returning an error if the running
+// kernel or the process's capabilities don't support the probes.
+//
+// Building this backend in requires the zehraguard_ebpf tag plus a compiled
+// input-timing object at Config.EBPFObjectPath, built out-of-tree from a C
+// source; neither the object nor its source is checked into this repo, so
+// ordinary
+// builds never pull in cilium/ebpf or attempt to load kprobes.
+type ebpfCollector struct {
+	objectPath string
+
+	mu     sync.Mutex
+	links  []link.Link
+	objs   ebpfObjects
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	keystrokes chan KeystrokeEvent
+	mice       chan MouseEvent
+}
+
+// ebpfObjects mirrors the generated bpf2go output for the input-timing
+// programs; the concrete maps/programs are defined in the accompanying
+// (kernel-side) .c source built out-of-tree.
+type ebpfObjects struct {
+	KeyEvents   *ebpf.Map
+	MouseEvents *ebpf.Map
+}
+
+func newEBPFCollector(cfg Config) (Collector, error) {
+	objectPath := cfg.EBPFObjectPath
+	if objectPath == "" {
+		objectPath = defaultEBPFObjectPath
+	}
+	return &ebpfCollector{
+		objectPath: objectPath,
+		keystrokes: make(chan KeystrokeEvent, 128),
+		mice:       make(chan MouseEvent, 128),
+	}, nil
+}
+
+func (c *ebpfCollector) Name() string                     { return "linux-ebpf" }
+func (c *ebpfCollector) Keystrokes() <-chan KeystrokeEvent { return c.keystrokes }
+func (c *ebpfCollector) Mice() <-chan MouseEvent           { return c.mice }
+
+func (c *ebpfCollector) Start(ctx context.Context) error {
+	objs, links, err := loadInputTimingPrograms(c.objectPath)
+	if err != nil {
+		return fmt.Errorf("ebpf: load input-timing programs: %w", err)
+	}
+
+	c.mu.Lock()
+	c.objs = objs
+	c.links = links
+	ctx, cancel := context.WithCancel(ctx)
+	c.cancel = cancel
+	c.mu.Unlock()
+
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		pollEBPFRingBuffers(ctx, objs, c.keystrokes, c.mice)
+	}()
+	return nil
+}
+
+func (c *ebpfCollector) Stop() error {
+	c.mu.Lock()
+	cancel := c.cancel
+	links := c.links
+	c.mu.Unlock()
+
+	if cancel != nil {
+		cancel()
+	}
+	c.wg.Wait()
+	for _, l := range links {
+		l.Close()
+	}
+	return nil
+}
+
+// loadInputTimingPrograms reads the compiled input-timing eBPF object from
+// objectPath and attaches its kprobes. Unlike the maps/programs it loads,
+// the object itself is not embedded in the binary: it depends on the
+// running kernel's BTF and isn't portable across the fleet, so it's
+// resolved at runtime rather than baked in at build time.
+func loadInputTimingPrograms(objectPath string) (ebpfObjects, []link.Link, error) {
+	raw, err := os.ReadFile(objectPath)
+	if err != nil {
+		return ebpfObjects{}, nil, fmt.Errorf("read %s: %w", objectPath, err)
+	}
+
+	spec, err := ebpf.LoadCollectionSpecFromReader(bytes.NewReader(raw))
+	if err != nil {
+		return ebpfObjects{}, nil, fmt.Errorf("load collection spec: %w", err)
+	}
+
+	coll, err := ebpf.NewCollection(spec)
+	if err != nil {
+		return ebpfObjects{}, nil, fmt.Errorf("new collection: %w", err)
+	}
+
+	objs := ebpfObjects{
+		KeyEvents:   coll.Maps["key_events"],
+		MouseEvents: coll.Maps["mouse_events"],
+	}
+
+	var links []link.Link
+	for name, prog := range coll.Programs {
+		kp, err := link.Kprobe(name, prog, nil)
+		if err != nil {
+			for _, l := range links {
+				l.Close()
+			}
+			return ebpfObjects{}, nil, fmt.Errorf("attach kprobe %s: %w", name, err)
+		}
+		links = append(links, kp)
+	}
+	return objs, links, nil
+}
+
+// pollEBPFRingBuffers reads timing records the kernel side pushed into the
+// key_events/mouse_events ring buffers and forwards them as
+// KeystrokeEvent/MouseEvent until ctx is cancelled.
+func pollEBPFRingBuffers(ctx context.Context, objs ebpfObjects, keys chan<- KeystrokeEvent, mice chan<- MouseEvent) {
+	keyReader, err := ringbuf.NewReader(objs.KeyEvents)
+	if err != nil {
+		return
+	}
+	defer keyReader.Close()
+
+	mouseReader, err := ringbuf.NewReader(objs.MouseEvents)
+	if err != nil {
+		return
+	}
+	defer mouseReader.Close()
+
+	go func() {
+		<-ctx.Done()
+		keyReader.Close()
+		mouseReader.Close()
+	}()
+
+	go func() {
+		for {
+			record, err := keyReader.Read()
+			if err != nil {
+				return
+			}
+			ev, ok := decodeKeyRecord(record.RawSample)
+			if !ok {
+				continue
+			}
+			select {
+			case keys <- ev:
+			default:
+			}
+		}
+	}()
+
+	for {
+		record, err := mouseReader.Read()
+		if err != nil {
+			return
+		}
+		ev, ok := decodeMouseRecord(record.RawSample)
+		if !ok {
+			continue
+		}
+		select {
+		case mice <- ev:
+		default:
+		}
+	}
+}
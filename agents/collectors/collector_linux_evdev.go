@@ -0,0 +1,137 @@
+//go:build linux
+
+package collectors
+
+import (
+	"context"
+	"encoding/binary"
+	"math"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Linux input event types/codes we care about (see linux/input-event-codes.h).
+const (
+	evKey = 0x01
+	evRel = 0x02
+	relX  = 0x00
+	relY  = 0x01
+)
+
+// inputEvent mirrors struct input_event from linux/input.h on 64-bit
+// systems (16-byte timeval + type/code/value).
+type inputEvent struct {
+	Sec   int64
+	Usec  int64
+	Type  uint16
+	Code  uint16
+	Value int32
+}
+
+func discoverInputDevices() ([]string, error) {
+	matches, err := filepath.Glob("/dev/input/event*")
+	if err != nil {
+		return nil, err
+	}
+	return matches, nil
+}
+
+// readEvdevLoop streams raw input_event records from a single evdev node
+// and turns key-down transitions and relative pointer motion into
+// KeystrokeEvent/MouseEvent values timed from the kernel-supplied event
+// timestamps rather than userspace wall clock: DwellTime is the interval
+// since the previous key-down, FlightTime is 0.8 times DwellTime, and
+// Velocity is the relative motion accumulated since the previous motion
+// event divided by the time between them.
+func readEvdevLoop(ctx context.Context, path string, keys chan<- KeystrokeEvent, mice chan<- MouseEvent) {
+	f, err := os.Open(path)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+
+	// f.Read below is a blocking syscall; ctx.Done() is only checked
+	// between reads, so a pending read needs its fd closed out from
+	// under it to actually unblock on cancellation.
+	go func() {
+		<-ctx.Done()
+		f.Close()
+	}()
+
+	var lastKeyTime time.Time
+	var seq int
+	var lastMoveTime time.Time
+	var dx, dy int32
+
+	buf := make([]byte, 24)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+
+		if _, err := f.Read(buf); err != nil {
+			return
+		}
+		var ev inputEvent
+		ev.Sec = int64(binary.LittleEndian.Uint64(buf[0:8]))
+		ev.Usec = int64(binary.LittleEndian.Uint64(buf[8:16]))
+		ev.Type = binary.LittleEndian.Uint16(buf[16:18])
+		ev.Code = binary.LittleEndian.Uint16(buf[18:20])
+		ev.Value = int32(binary.LittleEndian.Uint32(buf[20:24]))
+
+		ts := time.Unix(ev.Sec, ev.Usec*1000)
+
+		switch ev.Type {
+		case evKey:
+			if ev.Value != 1 { // only key-down transitions
+				continue
+			}
+			if lastKeyTime.IsZero() {
+				lastKeyTime = ts
+				continue
+			}
+			dwell := float64(ts.Sub(lastKeyTime).Nanoseconds()) / 1e6
+			lastKeyTime = ts
+			seq++
+			select {
+			case keys <- KeystrokeEvent{
+				KeyCode:    int(ev.Code),
+				DwellTime:  dwell,
+				FlightTime: dwell * 0.8,
+				Sequence:   path,
+			}:
+			default:
+			}
+		case evRel:
+			switch ev.Code {
+			case relX:
+				dx += int32(ev.Value)
+			case relY:
+				dy += int32(ev.Value)
+			}
+			if lastMoveTime.IsZero() {
+				lastMoveTime = ts
+				continue
+			}
+			dt := ts.Sub(lastMoveTime).Seconds()
+			if dt <= 0 {
+				continue
+			}
+			velocity := math.Sqrt(float64(dx*dx+dy*dy)) / dt
+			select {
+			case mice <- MouseEvent{
+				X:         int(dx),
+				Y:         int(dy),
+				Velocity:  velocity,
+				ClickType: "move",
+			}:
+			default:
+			}
+			dx, dy = 0, 0
+			lastMoveTime = ts
+		}
+	}
+}
@@ -0,0 +1,17 @@
+//go:build (!linux && !windows && !darwin) || (darwin && !cgo)
+
+package collectors
+
+import "fmt"
+
+// This also covers darwin built with CGO_ENABLED=0 (the default for a
+// cross-compile without a macOS SDK/toolchain): collector_darwin.go needs
+// cgo for CGEventTap, so without it there's no native collector, same as
+// any other unsupported platform.
+func platformCollector(name string, cfg Config) (Collector, bool, error) {
+	return nil, false, fmt.Errorf("no native input collector for this platform, use \"synthetic\"")
+}
+
+func defaultCollectorName(cfg Config) string {
+	return "synthetic"
+}
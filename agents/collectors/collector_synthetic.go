@@ -0,0 +1,137 @@
+package collectors
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+)
+
+// syntheticCollector reproduces the pre-eBPF placeholder behavior: it
+// invents plausible keystroke/mouse timing rather than hooking the OS. It
+// exists for CI and local testing where no real input device is available,
+// and is selected via Config.Names (e.g. []string{"synthetic"}), or by
+// default on platforms with no native collector.
+type syntheticCollector struct {
+	sampleRate int
+
+	mu     sync.Mutex
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	keystrokes chan KeystrokeEvent
+	mice       chan MouseEvent
+}
+
+// NewSynthetic returns a Collector that fabricates events at sampleRate
+// (events/sec for keystrokes; mouse events are emitted at a fixed 10Hz).
+func NewSynthetic(sampleRate int) Collector {
+	if sampleRate <= 0 {
+		sampleRate = 10
+	}
+	return &syntheticCollector{
+		sampleRate: sampleRate,
+		keystrokes: make(chan KeystrokeEvent, 32),
+		mice:       make(chan MouseEvent, 32),
+	}
+}
+
+func (c *syntheticCollector) Name() string { return "synthetic" }
+
+func (c *syntheticCollector) Keystrokes() <-chan KeystrokeEvent { return c.keystrokes }
+func (c *syntheticCollector) Mice() <-chan MouseEvent           { return c.mice }
+
+func (c *syntheticCollector) Start(ctx context.Context) error {
+	c.mu.Lock()
+	if c.cancel != nil {
+		c.mu.Unlock()
+		return fmt.Errorf("synthetic collector already started")
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	c.cancel = cancel
+	c.done = make(chan struct{})
+	c.mu.Unlock()
+
+	go c.run(ctx)
+	return nil
+}
+
+func (c *syntheticCollector) Stop() error {
+	c.mu.Lock()
+	cancel := c.cancel
+	done := c.done
+	c.cancel = nil
+	c.mu.Unlock()
+
+	if cancel == nil {
+		return nil
+	}
+	cancel()
+	<-done
+	return nil
+}
+
+func (c *syntheticCollector) run(ctx context.Context) {
+	defer close(c.done)
+
+	keyTicker := time.NewTicker(time.Second / time.Duration(c.sampleRate))
+	mouseTicker := time.NewTicker(time.Millisecond * 100)
+	defer keyTicker.Stop()
+	defer mouseTicker.Stop()
+
+	var lastKeyTime time.Time
+	var seq int
+	var lastX, lastY int
+	var lastMouseTime time.Time
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-keyTicker.C:
+			now := time.Now()
+			if lastKeyTime.IsZero() {
+				lastKeyTime = now
+				continue
+			}
+			dwell := float64(now.Sub(lastKeyTime).Nanoseconds()) / 1e6
+			lastKeyTime = now
+			seq++
+			select {
+			case c.keystrokes <- KeystrokeEvent{
+				KeyCode:     65 + (seq % 26),
+				DwellTime:   dwell,
+				FlightTime:  dwell * 0.8,
+				Pressure:    0.5 + float64(seq%50)/100.0,
+				TypingSpeed: 60.0 + float64(seq%40) - 20,
+				Sequence:    fmt.Sprintf("seq_%d", seq),
+			}:
+			default:
+			}
+		case <-mouseTicker.C:
+			now := time.Now()
+			newX := lastX + (int(now.Unix()) % 21) - 10
+			newY := lastY + (int(now.Unix()) % 21) - 10
+			if !lastMouseTime.IsZero() {
+				dt := now.Sub(lastMouseTime).Seconds()
+				dx := float64(newX - lastX)
+				dy := float64(newY - lastY)
+				velocity := math.Sqrt(dx*dx+dy*dy) / dt
+				select {
+				case c.mice <- MouseEvent{
+					X:            newX,
+					Y:            newY,
+					Velocity:     velocity,
+					Acceleration: velocity * 0.1,
+					ClickType:    "move",
+					Trajectory:   fmt.Sprintf("(%d,%d)", newX, newY),
+				}:
+				default:
+				}
+			}
+			lastX, lastY = newX, newY
+			lastMouseTime = now
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package collectors
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestNewSyntheticDefaultsSampleRate(t *testing.T) {
+	c := NewSynthetic(0).(*syntheticCollector)
+	if c.sampleRate != 10 {
+		t.Fatalf("sampleRate = %d, want default of 10", c.sampleRate)
+	}
+	if got := c.Name(); got != "synthetic" {
+		t.Fatalf("Name() = %q, want %q", got, "synthetic")
+	}
+}
+
+func TestSyntheticCollectorStartStop(t *testing.T) {
+	c := NewSynthetic(1000)
+	if err := c.Start(context.Background()); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	if err := c.Start(context.Background()); err == nil {
+		t.Fatal("Start while running should return an error")
+	}
+	if err := c.Stop(); err != nil {
+		t.Fatalf("Stop: %v", err)
+	}
+	// Stop is idempotent.
+	if err := c.Stop(); err != nil {
+		t.Fatalf("second Stop: %v", err)
+	}
+}
+
+func TestSyntheticCollectorKeystrokeTiming(t *testing.T) {
+	c := NewSynthetic(1000) // 1ms period so the test doesn't have to wait long
+	if err := c.Start(context.Background()); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer c.Stop()
+
+	select {
+	case evt := <-c.Keystrokes():
+		if evt.DwellTime <= 0 {
+			t.Fatalf("DwellTime = %v, want > 0", evt.DwellTime)
+		}
+		if got, want := evt.FlightTime, evt.DwellTime*0.8; got != want {
+			t.Fatalf("FlightTime = %v, want %v (DwellTime * 0.8)", got, want)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for a keystroke event")
+	}
+}
+
+func TestSyntheticCollectorMouseVelocityNonNegative(t *testing.T) {
+	c := NewSynthetic(1000)
+	if err := c.Start(context.Background()); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer c.Stop()
+
+	select {
+	case evt := <-c.Mice():
+		if evt.Velocity < 0 {
+			t.Fatalf("Velocity = %v, want >= 0", evt.Velocity)
+		}
+		if got, want := evt.Acceleration, evt.Velocity*0.1; got != want {
+			t.Fatalf("Acceleration = %v, want %v (Velocity * 0.1)", got, want)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for a mouse event")
+	}
+}
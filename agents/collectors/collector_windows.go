@@ -0,0 +1,169 @@
+//go:build windows
+
+package collectors
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"runtime"
+	"sync"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+// windowsHookCollector installs a low-level keyboard (WH_KEYBOARD_LL) and
+// mouse (WH_MOUSE_LL) hook via SetWindowsHookEx and times events from the
+// timestamps Windows attaches to each hook callback (MSLLHOOKSTRUCT.time /
+// KBDLLHOOKSTRUCT.time): DwellTime is the interval between successive
+// keyboard callbacks, key-down and key-up alike, FlightTime is 0.8 times
+// DwellTime, and Velocity is pointer distance over the time between mouse
+// callbacks.
+type windowsHookCollector struct {
+	mu          sync.Mutex
+	keyHook     windows.Handle
+	mouseHook   windows.Handle
+	msgLoopDone chan struct{}
+	cancel      context.CancelFunc
+
+	keystrokes chan KeystrokeEvent
+	mice       chan MouseEvent
+
+	lastKeyTick   uint32
+	lastMouseTick uint32
+	lastX, lastY  int32
+}
+
+func newWindowsHookCollector() *windowsHookCollector {
+	return &windowsHookCollector{
+		keystrokes: make(chan KeystrokeEvent, 64),
+		mice:       make(chan MouseEvent, 64),
+	}
+}
+
+func (c *windowsHookCollector) Name() string                     { return "windows-hook" }
+func (c *windowsHookCollector) Keystrokes() <-chan KeystrokeEvent { return c.keystrokes }
+func (c *windowsHookCollector) Mice() <-chan MouseEvent           { return c.mice }
+
+func (c *windowsHookCollector) Start(ctx context.Context) error {
+	c.mu.Lock()
+	ctx, cancel := context.WithCancel(ctx)
+	c.cancel = cancel
+	c.msgLoopDone = make(chan struct{})
+	c.mu.Unlock()
+
+	// SetWindowsHookEx must be called and pumped from the same thread, so
+	// the hook installation and message loop run together on a locked OS
+	// thread for the collector's lifetime.
+	go c.runHookThread(ctx)
+	return nil
+}
+
+func (c *windowsHookCollector) Stop() error {
+	c.mu.Lock()
+	cancel := c.cancel
+	done := c.msgLoopDone
+	c.mu.Unlock()
+	if cancel == nil {
+		return nil
+	}
+	cancel()
+	<-done
+	return nil
+}
+
+func (c *windowsHookCollector) runHookThread(ctx context.Context) {
+	defer close(c.msgLoopDone)
+
+	// SetWindowsHookEx/GetMessage have hard thread-affinity requirements;
+	// without locking, the Go scheduler could migrate this goroutine to a
+	// different OS thread between install and the message loop.
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	keyHook, err := installLowLevelHook(whKeyboardLL, c.onKeyboardEvent)
+	if err != nil {
+		return
+	}
+	defer unhookWindowsHookEx(keyHook)
+
+	mouseHook, err := installLowLevelHook(whMouseLL, c.onMouseEvent)
+	if err != nil {
+		return
+	}
+	defer unhookWindowsHookEx(mouseHook)
+
+	c.mu.Lock()
+	c.keyHook, c.mouseHook = keyHook, mouseHook
+	c.mu.Unlock()
+
+	runMessageLoopUntil(ctx)
+}
+
+func (c *windowsHookCollector) onKeyboardEvent(tickMs uint32, vkCode uint32) {
+	if c.lastKeyTick == 0 {
+		c.lastKeyTick = tickMs
+		return
+	}
+	dwell := float64(tickMs - c.lastKeyTick)
+	c.lastKeyTick = tickMs
+	select {
+	case c.keystrokes <- KeystrokeEvent{
+		KeyCode:    int(vkCode),
+		DwellTime:  dwell,
+		FlightTime: dwell * 0.8,
+	}:
+	default:
+	}
+}
+
+func (c *windowsHookCollector) onMouseEvent(tickMs uint32, x, y int32) {
+	if c.lastMouseTick == 0 {
+		c.lastMouseTick, c.lastX, c.lastY = tickMs, x, y
+		return
+	}
+	dt := float64(tickMs-c.lastMouseTick) / 1000.0
+	dx, dy := float64(x-c.lastX), float64(y-c.lastY)
+	c.lastMouseTick, c.lastX, c.lastY = tickMs, x, y
+	if dt <= 0 {
+		return
+	}
+	select {
+	case c.mice <- MouseEvent{
+		X:         int(x),
+		Y:         int(y),
+		Velocity:  math.Sqrt(dx*dx+dy*dy) / dt,
+		ClickType: "move",
+	}:
+	default:
+	}
+}
+
+const (
+	whKeyboardLL = 13
+	whMouseLL    = 14
+)
+
+// installLowLevelHook, unhookWindowsHookEx and runMessageLoopUntil wrap the
+// SetWindowsHookEx/UnhookWindowsHookEx/GetMessage Win32 calls via
+// golang.org/x/sys/windows; kept in their own file (collector_windows_hook.go)
+// since they deal directly with syscall-level structs and callbacks.
+var _ = unsafe.Pointer(nil)
+
+func fmtErr(op string, err error) error {
+	return fmt.Errorf("collectors: %s: %w", op, err)
+}
+
+func platformCollector(name string, cfg Config) (Collector, bool, error) {
+	switch name {
+	case "windows-hook":
+		return newWindowsHookCollector(), true, nil
+	default:
+		return nil, false, nil
+	}
+}
+
+func defaultCollectorName(cfg Config) string {
+	return "windows-hook"
+}
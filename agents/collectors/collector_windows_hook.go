@@ -0,0 +1,92 @@
+//go:build windows
+
+package collectors
+
+import (
+	"context"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+var (
+	modUser32              = windows.NewLazySystemDLL("user32.dll")
+	procSetWindowsHookExW  = modUser32.NewProc("SetWindowsHookExW")
+	procUnhookWindowsHook  = modUser32.NewProc("UnhookWindowsHookEx")
+	procCallNextHookEx     = modUser32.NewProc("CallNextHookEx")
+	procGetMessageW        = modUser32.NewProc("GetMessageW")
+)
+
+// kbdllHookStruct mirrors KBDLLHOOKSTRUCT.
+type kbdllHookStruct struct {
+	VkCode      uint32
+	ScanCode    uint32
+	Flags       uint32
+	Time        uint32
+	ExtraInfo   uintptr
+}
+
+// msllHookStruct mirrors MSLLHOOKSTRUCT.
+type msllHookStruct struct {
+	Pt        struct{ X, Y int32 }
+	MouseData uint32
+	Flags     uint32
+	Time      uint32
+	ExtraInfo uintptr
+}
+
+func installLowLevelHook(idHook int, onEvent interface{}) (windows.Handle, error) {
+	var callback uintptr
+	switch cb := onEvent.(type) {
+	case func(tickMs uint32, vkCode uint32):
+		callback = windows.NewCallback(func(nCode int, wParam, lParam uintptr) uintptr {
+			if nCode >= 0 {
+				kh := (*kbdllHookStruct)(unsafe.Pointer(lParam))
+				cb(kh.Time, kh.VkCode)
+			}
+			ret, _, _ := procCallNextHookEx.Call(0, uintptr(nCode), wParam, lParam)
+			return ret
+		})
+	case func(tickMs uint32, x, y int32):
+		callback = windows.NewCallback(func(nCode int, wParam, lParam uintptr) uintptr {
+			if nCode >= 0 {
+				mh := (*msllHookStruct)(unsafe.Pointer(lParam))
+				cb(mh.Time, mh.Pt.X, mh.Pt.Y)
+			}
+			ret, _, _ := procCallNextHookEx.Call(0, uintptr(nCode), wParam, lParam)
+			return ret
+		})
+	}
+
+	h, _, err := procSetWindowsHookExW.Call(uintptr(idHook), callback, 0, 0)
+	if h == 0 {
+		return 0, err
+	}
+	return windows.Handle(h), nil
+}
+
+func unhookWindowsHookEx(h windows.Handle) {
+	procUnhookWindowsHook.Call(uintptr(h))
+}
+
+// runMessageLoopUntil pumps the thread's message queue (required for
+// low-level hooks to fire) until ctx is cancelled.
+func runMessageLoopUntil(ctx context.Context) {
+	type msg struct {
+		hwnd    uintptr
+		message uint32
+		wParam  uintptr
+		lParam  uintptr
+		time    uint32
+		pt      struct{ X, Y int32 }
+	}
+	var m msg
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+		procGetMessageW.Call(uintptr(unsafe.Pointer(&m)), 0, 0, 0)
+	}
+}
@@ -0,0 +1,108 @@
+package transport
+
+import (
+	"context"
+	"log"
+	"math/rand"
+	"time"
+)
+
+// Multiplexer fans events out to the transport that matches their route
+// priority, falling back to the next transport in the chain rather than
+// silently dropping the event. It does not reconnect transports itself;
+// WebSocketTransport, QUICTransport and WebRTCTransport each redial in the
+// background via Reconnector.
+type Multiplexer struct {
+	route Route
+
+	// chains[priority] lists transports in preference order, e.g.
+	// [webrtc, quic, websocket, redis] for PriorityLowLatency and
+	// [redis] for PriorityBatched.
+	chains map[Priority][]Transport
+
+	backoff BackoffConfig
+}
+
+// BackoffConfig controls the reconnect pacing of Reconnector.
+type BackoffConfig struct {
+	Initial    time.Duration
+	Max        time.Duration
+	Multiplier float64
+}
+
+// DefaultBackoff matches the pacing used elsewhere in the agent for
+// reconnect loops.
+func DefaultBackoff() BackoffConfig {
+	return BackoffConfig{Initial: time.Second, Max: time.Minute, Multiplier: 2}
+}
+
+// NewMultiplexer builds a Multiplexer from a route and, for each priority,
+// an ordered list of transports to try. backoff is stored on the
+// Multiplexer; Send does not use it.
+func NewMultiplexer(route Route, chains map[Priority][]Transport, backoff BackoffConfig) *Multiplexer {
+	return &Multiplexer{route: route, chains: chains, backoff: backoff}
+}
+
+// Send routes payload for eventType through its priority's transport
+// chain, trying each transport in order until one succeeds.
+func (m *Multiplexer) Send(ctx context.Context, eventType string, payload []byte) error {
+	priority := m.route[eventType]
+	chain := m.chains[priority]
+
+	var lastErr error
+	for _, t := range chain {
+		if err := t.Send(ctx, payload); err != nil {
+			lastErr = err
+			log.Printf("transport %s: send failed, trying next: %v", t.Name(), err)
+			continue
+		}
+		return nil
+	}
+	return lastErr
+}
+
+// Close closes every transport across every chain.
+func (m *Multiplexer) Close() error {
+	var lastErr error
+	seen := make(map[Transport]bool)
+	for _, chain := range m.chains {
+		for _, t := range chain {
+			if seen[t] {
+				continue
+			}
+			seen[t] = true
+			if err := t.Close(); err != nil {
+				lastErr = err
+			}
+		}
+	}
+	return lastErr
+}
+
+// Reconnector calls reconnect with exponential backoff and jitter until it
+// succeeds or ctx is cancelled, useful for transports that need a fresh
+// dial after Health() reports failure.
+func Reconnector(ctx context.Context, cfg BackoffConfig, reconnect func(ctx context.Context) error) error {
+	delay := cfg.Initial
+	for {
+		err := reconnect(ctx)
+		if err == nil {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(jitter(delay)):
+		}
+
+		delay = time.Duration(float64(delay) * cfg.Multiplier)
+		if delay > cfg.Max {
+			delay = cfg.Max
+		}
+	}
+}
+
+func jitter(d time.Duration) time.Duration {
+	return d/2 + time.Duration(rand.Int63n(int64(d/2+1)))
+}
@@ -0,0 +1,143 @@
+package transport
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"sync"
+
+	"github.com/quic-go/quic-go"
+)
+
+// QUICTransport streams events over a single QUIC connection to the
+// ZehraGuard server, one unidirectional stream per Send call, giving
+// low-latency delivery for agents that can't establish a WebRTC data
+// channel (QUIC itself runs over UDP, so the server's QUIC port must be
+// reachable over UDP). When a stream
+// fails it drops the connection and redials in the background with
+// exponential backoff (via Reconnector), the same as WebSocketTransport.
+type QUICTransport struct {
+	addr    string
+	tlsConf *tls.Config
+	backoff BackoffConfig
+
+	mu           sync.Mutex
+	conn         quic.Connection
+	closed       bool
+	reconnecting bool
+}
+
+// NewQUICTransport dials addr (host:port) immediately.
+func NewQUICTransport(ctx context.Context, addr string, tlsConf *tls.Config) (*QUICTransport, error) {
+	if tlsConf == nil {
+		tlsConf = &tls.Config{NextProtos: []string{"zehraguard-telemetry"}}
+	}
+
+	t := &QUICTransport{addr: addr, tlsConf: tlsConf, backoff: DefaultBackoff()}
+	if err := t.dial(ctx); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+func (t *QUICTransport) dial(ctx context.Context) error {
+	conn, err := quic.DialAddr(ctx, t.addr, t.tlsConf, nil)
+	if err != nil {
+		return fmt.Errorf("quic transport: dial: %w", err)
+	}
+	t.mu.Lock()
+	t.conn = conn
+	t.mu.Unlock()
+	return nil
+}
+
+func (t *QUICTransport) Name() string { return "quic" }
+
+func (t *QUICTransport) Send(ctx context.Context, payload []byte) error {
+	t.mu.Lock()
+	conn := t.conn
+	t.mu.Unlock()
+	if conn == nil {
+		return fmt.Errorf("quic transport: not connected")
+	}
+
+	stream, err := conn.OpenUniStreamSync(ctx)
+	if err != nil {
+		t.dropConn(conn)
+		return fmt.Errorf("quic transport: open stream: %w", err)
+	}
+	defer stream.Close()
+
+	if _, err := stream.Write(payload); err != nil {
+		t.dropConn(conn)
+		return fmt.Errorf("quic transport: write: %w", err)
+	}
+	return nil
+}
+
+// dropConn clears the connection if it's still the one that just failed,
+// and starts a background redial loop (unless one is already running or
+// the transport has been closed). Reconnect uses a background context: the
+// caller's ctx belongs to a single Send call and would cancel the redial
+// as soon as that call returns.
+func (t *QUICTransport) dropConn(failed quic.Connection) {
+	t.mu.Lock()
+	if t.conn == failed {
+		t.conn = nil
+	}
+	failed.CloseWithError(0, "send failed")
+	shouldReconnect := !t.closed && !t.reconnecting && t.conn == nil
+	if shouldReconnect {
+		t.reconnecting = true
+	}
+	t.mu.Unlock()
+
+	if !shouldReconnect {
+		return
+	}
+	go t.reconnectLoop()
+}
+
+func (t *QUICTransport) reconnectLoop() {
+	defer func() {
+		t.mu.Lock()
+		t.reconnecting = false
+		t.mu.Unlock()
+	}()
+
+	Reconnector(context.Background(), t.backoff, func(ctx context.Context) error {
+		t.mu.Lock()
+		closed := t.closed
+		t.mu.Unlock()
+		if closed {
+			return nil
+		}
+		return t.dial(ctx)
+	})
+}
+
+func (t *QUICTransport) Health() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.conn == nil {
+		return fmt.Errorf("quic transport: not connected")
+	}
+	select {
+	case <-t.conn.Context().Done():
+		return fmt.Errorf("quic transport: connection closed")
+	default:
+		return nil
+	}
+}
+
+func (t *QUICTransport) Close() error {
+	t.mu.Lock()
+	t.closed = true
+	conn := t.conn
+	t.conn = nil
+	t.mu.Unlock()
+	if conn == nil {
+		return nil
+	}
+	return conn.CloseWithError(0, "agent shutdown")
+}
@@ -0,0 +1,40 @@
+package transport
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// RedisTransport pushes event payloads onto a Redis list for the backend's
+// ingest workers to drain. It is the durable, batched transport. The
+// client may be a single-node, Sentinel-failover, or Cluster client, since
+// all three satisfy redis.UniversalClient.
+type RedisTransport struct {
+	client redis.UniversalClient
+	key    string
+}
+
+// NewRedisTransport wraps an existing Redis client. key is the list the
+// payloads are LPUSHed onto (e.g. "behavioral_events").
+func NewRedisTransport(client redis.UniversalClient, key string) *RedisTransport {
+	return &RedisTransport{client: client, key: key}
+}
+
+func (t *RedisTransport) Name() string { return "redis" }
+
+func (t *RedisTransport) Send(ctx context.Context, payload []byte) error {
+	if err := t.client.LPush(ctx, t.key, payload).Err(); err != nil {
+		return fmt.Errorf("redis transport: %w", err)
+	}
+	return nil
+}
+
+func (t *RedisTransport) Health() error {
+	return t.client.Ping(context.Background()).Err()
+}
+
+func (t *RedisTransport) Close() error {
+	return t.client.Close()
+}
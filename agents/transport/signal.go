@@ -0,0 +1,98 @@
+package transport
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/pion/webrtc/v3"
+)
+
+// negotiateViaSignalServer performs the SDP offer/answer exchange against
+// the server-side coordinator endpoint (POST /agents/webrtc/offer), which
+// hands back the SDP answer; the ICE/DERP configuration is fetched
+// separately by FetchICEConfig. token, if non-empty, is sent as a bearer token the
+// same way the agent authenticates its other manager requests.
+func negotiateViaSignalServer(ctx context.Context, signalURL, token string, pc *webrtc.PeerConnection) error {
+	offer, err := pc.CreateOffer(nil)
+	if err != nil {
+		return fmt.Errorf("create offer: %w", err)
+	}
+
+	gatherComplete := webrtc.GatheringCompletePromise(pc)
+	if err := pc.SetLocalDescription(offer); err != nil {
+		return fmt.Errorf("set local description: %w", err)
+	}
+
+	// Wait for ICE gathering to finish so the offer carries this agent's
+	// STUN/TURN-reflexive and relay candidates; posting immediately after
+	// SetLocalDescription would typically send only host candidates,
+	// which can't traverse a NAT or corporate firewall.
+	select {
+	case <-gatherComplete:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	body, err := json.Marshal(pc.LocalDescription())
+	if err != nil {
+		return fmt.Errorf("marshal offer: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, signalURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("build signal request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("signal exchange: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("signal exchange: unexpected status %d", resp.StatusCode)
+	}
+
+	var answer webrtc.SessionDescription
+	if err := json.NewDecoder(resp.Body).Decode(&answer); err != nil {
+		return fmt.Errorf("decode answer: %w", err)
+	}
+
+	return pc.SetRemoteDescription(answer)
+}
+
+// FetchICEConfig asks the coordinator for the STUN/DERP-style relay
+// configuration to use, so operators can rotate TURN credentials without
+// redeploying agents. token is sent the same way as negotiateViaSignalServer.
+func FetchICEConfig(ctx context.Context, coordinatorURL, token string) (ICEConfig, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, coordinatorURL+"/agents/webrtc/ice-config", nil)
+	if err != nil {
+		return ICEConfig{}, err
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return ICEConfig{}, fmt.Errorf("fetch ice config: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return ICEConfig{}, fmt.Errorf("fetch ice config: unexpected status %d", resp.StatusCode)
+	}
+
+	var cfg ICEConfig
+	if err := json.NewDecoder(resp.Body).Decode(&cfg); err != nil {
+		return ICEConfig{}, fmt.Errorf("decode ice config: %w", err)
+	}
+	return cfg, nil
+}
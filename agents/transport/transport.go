@@ -0,0 +1,54 @@
+// Package transport defines the pluggable telemetry transports the
+// behavioral agent uses to ship events to the ZehraGuard backend, and a
+// Multiplexer that routes each event to the transport best suited to its
+// latency/durability needs.
+package transport
+
+import "context"
+
+// Transport delivers a single event payload to the backend and reports its
+// own health so the Multiplexer can route around a failing transport.
+type Transport interface {
+	// Name identifies the transport, e.g. "redis", "websocket", "webrtc",
+	// "quic".
+	Name() string
+
+	// Send delivers payload (already-marshaled event bytes). It must not
+	// block indefinitely; implementations should respect ctx.
+	Send(ctx context.Context, payload []byte) error
+
+	// Health reports whether the transport is currently usable.
+	Health() error
+
+	// Close releases any connections or background goroutines.
+	Close() error
+}
+
+// Priority selects which transport class an event type prefers.
+type Priority int
+
+const (
+	// PriorityLowLatency routes to the fastest available transport
+	// (WebRTC/QUIC), falling back to WebSocket/Redis if unavailable.
+	// Intended for interactive signals like keystroke/mouse dynamics.
+	PriorityLowLatency Priority = iota
+
+	// PriorityBatched routes to the durable, batched transport (Redis).
+	// Intended for higher-volume, latency-tolerant events like file and
+	// network activity.
+	PriorityBatched
+)
+
+// Route maps an event type to the priority class it should be sent with.
+type Route map[string]Priority
+
+// DefaultRoute matches the event types BehavioralAgent already emits.
+func DefaultRoute() Route {
+	return Route{
+		"keystroke":        PriorityLowLatency,
+		"mouse_movement":   PriorityLowLatency,
+		"file_access":      PriorityBatched,
+		"network_request":  PriorityBatched,
+		"system_activity":  PriorityBatched,
+	}
+}
@@ -0,0 +1,167 @@
+package transport
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/pion/webrtc/v3"
+)
+
+// ICEConfig carries the STUN/TURN (DERP-style relay) servers the
+// coordinator hands out to agents on registration.
+type ICEConfig struct {
+	STUNServers []string
+	TURNServers []webrtc.ICEServer
+}
+
+// WebRTCTransport streams events over an unreliable, unordered WebRTC data
+// channel for the lowest possible latency (keystroke/mouse dynamics). It
+// falls back to the caller's next transport if the peer connection or data
+// channel is not yet open. When the channel closes or a send fails, it
+// renegotiates a fresh peer connection in the background with exponential
+// backoff (via Reconnector), the same as WebSocketTransport/QUICTransport.
+type WebRTCTransport struct {
+	signalURL string
+	token     string
+	ice       ICEConfig
+	backoff   BackoffConfig
+
+	mu           sync.Mutex
+	pc           *webrtc.PeerConnection
+	channel      *webrtc.DataChannel
+	open         bool
+	closed       bool
+	reconnecting bool
+}
+
+// NewWebRTCTransport creates the local peer connection and data channel and
+// begins ICE negotiation with the coordinator at signalURL, authenticating
+// with token (may be empty). Signaling itself (SDP offer/answer exchange)
+// is carried out by the coordinator client in the transport/signal.go
+// helper.
+func NewWebRTCTransport(ctx context.Context, signalURL, token string, ice ICEConfig) (*WebRTCTransport, error) {
+	t := &WebRTCTransport{signalURL: signalURL, token: token, ice: ice, backoff: DefaultBackoff()}
+	if err := t.connect(ctx); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// connect negotiates a fresh peer connection and data channel, wiring their
+// callbacks to this transport before replacing t.pc/t.channel.
+func (t *WebRTCTransport) connect(ctx context.Context) error {
+	servers := []webrtc.ICEServer{{URLs: t.ice.STUNServers}}
+	servers = append(servers, t.ice.TURNServers...)
+
+	pc, err := webrtc.NewPeerConnection(webrtc.Configuration{ICEServers: servers})
+	if err != nil {
+		return fmt.Errorf("webrtc transport: new peer connection: %w", err)
+	}
+
+	dc, err := pc.CreateDataChannel("zehraguard-telemetry", &webrtc.DataChannelInit{
+		Ordered: boolPtr(false),
+	})
+	if err != nil {
+		pc.Close()
+		return fmt.Errorf("webrtc transport: create data channel: %w", err)
+	}
+
+	dc.OnOpen(func() {
+		t.mu.Lock()
+		t.open = true
+		t.mu.Unlock()
+	})
+	dc.OnClose(func() {
+		t.dropConn(pc)
+	})
+
+	if err := negotiateViaSignalServer(ctx, t.signalURL, t.token, pc); err != nil {
+		pc.Close()
+		return fmt.Errorf("webrtc transport: negotiate: %w", err)
+	}
+
+	t.mu.Lock()
+	t.pc, t.channel = pc, dc
+	t.mu.Unlock()
+	return nil
+}
+
+func (t *WebRTCTransport) Name() string { return "webrtc" }
+
+func (t *WebRTCTransport) Send(ctx context.Context, payload []byte) error {
+	t.mu.Lock()
+	open, ch, pc := t.open, t.channel, t.pc
+	t.mu.Unlock()
+	if !open {
+		return fmt.Errorf("webrtc transport: data channel not open")
+	}
+	if err := ch.Send(payload); err != nil {
+		t.dropConn(pc)
+		return fmt.Errorf("webrtc transport: %w", err)
+	}
+	return nil
+}
+
+// dropConn tears down the peer connection if it's still the one that just
+// failed, and starts a background redial loop (unless one is already
+// running or the transport has been closed).
+func (t *WebRTCTransport) dropConn(failed *webrtc.PeerConnection) {
+	t.mu.Lock()
+	if t.pc == failed {
+		t.pc, t.channel, t.open = nil, nil, false
+	}
+	shouldReconnect := !t.closed && !t.reconnecting && t.pc == nil
+	if shouldReconnect {
+		t.reconnecting = true
+	}
+	t.mu.Unlock()
+
+	failed.Close()
+
+	if !shouldReconnect {
+		return
+	}
+	go t.reconnectLoop()
+}
+
+func (t *WebRTCTransport) reconnectLoop() {
+	defer func() {
+		t.mu.Lock()
+		t.reconnecting = false
+		t.mu.Unlock()
+	}()
+
+	Reconnector(context.Background(), t.backoff, func(ctx context.Context) error {
+		t.mu.Lock()
+		closed := t.closed
+		t.mu.Unlock()
+		if closed {
+			return nil
+		}
+		return t.connect(ctx)
+	})
+}
+
+func (t *WebRTCTransport) Health() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if !t.open {
+		return fmt.Errorf("webrtc transport: data channel not open")
+	}
+	return nil
+}
+
+func (t *WebRTCTransport) Close() error {
+	t.mu.Lock()
+	t.closed = true
+	pc := t.pc
+	t.pc, t.channel, t.open = nil, nil, false
+	t.mu.Unlock()
+	if pc == nil {
+		return nil
+	}
+	return pc.Close()
+}
+
+func boolPtr(b bool) *bool { return &b }
@@ -0,0 +1,123 @@
+package transport
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/gorilla/websocket"
+)
+
+// WebSocketTransport writes event payloads to a single long-lived
+// websocket connection to the ZehraGuard server. When a write fails it
+// drops the broken connection and redials in the background with
+// exponential backoff (via Reconnector) rather than leaving every
+// subsequent Send fail against a dead socket.
+type WebSocketTransport struct {
+	url     string
+	backoff BackoffConfig
+
+	mu           sync.Mutex
+	conn         *websocket.Conn
+	closed       bool
+	reconnecting bool
+}
+
+// NewWebSocketTransport dials url immediately; url should already include
+// the /ws/agent/<userID> path.
+func NewWebSocketTransport(url string) (*WebSocketTransport, error) {
+	t := &WebSocketTransport{url: url, backoff: DefaultBackoff()}
+	if err := t.dial(); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+func (t *WebSocketTransport) dial() error {
+	conn, _, err := websocket.DefaultDialer.Dial(t.url, nil)
+	if err != nil {
+		return fmt.Errorf("websocket transport: dial: %w", err)
+	}
+	t.mu.Lock()
+	t.conn = conn
+	t.mu.Unlock()
+	return nil
+}
+
+func (t *WebSocketTransport) Name() string { return "websocket" }
+
+func (t *WebSocketTransport) Send(ctx context.Context, payload []byte) error {
+	t.mu.Lock()
+	conn := t.conn
+	t.mu.Unlock()
+	if conn == nil {
+		return fmt.Errorf("websocket transport: not connected")
+	}
+	if err := conn.WriteMessage(websocket.TextMessage, payload); err != nil {
+		t.dropConn(conn)
+		return fmt.Errorf("websocket transport: %w", err)
+	}
+	return nil
+}
+
+// dropConn clears the connection if it's still the one that just failed,
+// and starts a background redial loop (unless one is already running or
+// the transport has been closed). Reconnect uses a background context: the
+// caller's ctx belongs to a single Send call and would cancel the redial
+// as soon as that call returns.
+func (t *WebSocketTransport) dropConn(failed *websocket.Conn) {
+	t.mu.Lock()
+	if t.conn == failed {
+		t.conn = nil
+	}
+	failed.Close()
+	shouldReconnect := !t.closed && !t.reconnecting && t.conn == nil
+	if shouldReconnect {
+		t.reconnecting = true
+	}
+	t.mu.Unlock()
+
+	if !shouldReconnect {
+		return
+	}
+	go t.reconnectLoop()
+}
+
+func (t *WebSocketTransport) reconnectLoop() {
+	defer func() {
+		t.mu.Lock()
+		t.reconnecting = false
+		t.mu.Unlock()
+	}()
+
+	Reconnector(context.Background(), t.backoff, func(ctx context.Context) error {
+		t.mu.Lock()
+		closed := t.closed
+		t.mu.Unlock()
+		if closed {
+			return nil
+		}
+		return t.dial()
+	})
+}
+
+func (t *WebSocketTransport) Health() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.conn == nil {
+		return fmt.Errorf("websocket transport: not connected")
+	}
+	return nil
+}
+
+func (t *WebSocketTransport) Close() error {
+	t.mu.Lock()
+	t.closed = true
+	conn := t.conn
+	t.conn = nil
+	t.mu.Unlock()
+	if conn == nil {
+		return nil
+	}
+	return conn.Close()
+}